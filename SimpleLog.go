@@ -1,13 +1,22 @@
 package SimpleLog
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"reflect"
 	"runtime/debug"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Level int
@@ -22,19 +31,85 @@ const (
 	PanicLevel
 )
 
+var levelNames = [...]string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL", "PANIC"}
+
+func (level Level) String() string {
+	if level >= 0 && int(level) < len(levelNames) {
+		return levelNames[level]
+	}
+	return "Level(" + strconv.Itoa(int(level)) + ")"
+}
+
 // 全局唯一的日志实例, 统一控制 level
 type logger struct {
 	*sync.Mutex
 	Out   io.Writer
-	Level Level
+	Level Level       // 只读, 修改请使用 SetLevel 或 Apply
+	live  *liveConfig // Level 等在锁外读取的副本
+
+	verbosity int
+
+	enabler          func(level Level, banner string) bool
+	filter           func(e *Event) bool
+	buffers          []*bufferedWriter
+	gap              time.Duration
+	durPrecision     time.Duration
+	now              func() time.Time                                 // 仅供测试注入时钟
+	tick             func(d time.Duration) (<-chan time.Time, func()) // 仅供测试注入定时器
+	sameDayLayout    string
+	levelTimeFormats map[Level]string
+
+	onError       func(err error)
+	metaOut       io.Writer
+	jsonOut       io.Writer
+	writeTimeout  time.Duration
+	syncLevel     Level
+	maxLineBytes  int
+	pending       *pendingWrite
+	counters      *counters
+	exit          func(code int)
+	exitCode      int
+	exitCodeFunc  func(level Level) int
+	fatalOnce     *sync.Once
+	keySampleRate float64
+	breaker       *circuitBreaker
+	ring          *RingWriter
+	maxStackDepth int
+	showDepth     bool
+	closed        bool // 已 Close, 由锁保护
+	postClose     PostCloseBehavior
+	metrics       *metricSet
+	hbQuiet       time.Duration
+}
+
+// clone 复制一份共享配置, 切片截断容量以免双方 append 时互相覆盖
+func (l *logger) clone() *logger {
+	l.Lock()
+	c := *l
+	l.Unlock()
+	c.live = l.live.clone()
+	c.buffers = slices.Clip(l.buffers)
+	return &c
 }
 
 // 外部接口, 自定义某些选项
 type Logger struct {
 	*logger
-	banner        string
-	color         bool
-	escapeNewline bool
+	view         *atomic.Pointer[settings] // banner, color 等可由 Apply 替换的设置
+	gutter       bool
+	skipEmpty    bool
+	fields       []Field
+	providers    []func() (key string, value any)
+	timeColor    string
+	bannerColor  string
+	quoting      FieldQuoting
+	nop          bool // Sampled 未采样时返回的实例
+	colorFunc    func(level Level, msg string) (prefix, suffix string)
+	levelColors  map[Level]string
+	ansiReset    string
+	omitEmptyMsg bool
+	nilText      string
+	lnav         bool
 }
 
 var (
@@ -56,108 +131,743 @@ var (
 		FatalLevel: "\x1b[91;5m[FATAL]\x1b[m",
 		PanicLevel: "\x1b[91;5;7m[PANIC]\x1b[m",
 	}
+	// LevelGutterC SetGutter 使用的行首标记, 每个都只占一列以保持对齐
+	LevelGutterC = map[Level]string{
+		TraceLevel: "\x1b[94m▌\x1b[m",
+		DebugLevel: "\x1b[92m▌\x1b[m",
+		InfoLevel:  "\x1b[97m▌\x1b[m",
+		WarnLevel:  "\x1b[93m▌\x1b[m",
+		ErrorLevel: "\x1b[91m▌\x1b[m",
+		FatalLevel: "\x1b[91m█\x1b[m",
+		PanicLevel: "\x1b[91m█\x1b[m",
+	}
 )
 
-var defaultLogger = &logger{
-	Out:   os.Stderr,
-	Mutex: new(sync.Mutex),
+var defaultLogger = newLogger(os.Stderr)
+
+func newLogger(out io.Writer) *logger {
+	return &logger{
+		Mutex:         new(sync.Mutex),
+		Out:           out,
+		live:          new(liveConfig),
+		syncLevel:     levelOff,
+		counters:      new(counters),
+		pending:       new(pendingWrite),
+		metrics:       new(metricSet),
+		exit:          os.Exit,
+		exitCode:      1,
+		sameDayLayout: "[15:04:05.000]",
+		fatalOnce:     new(sync.Once),
+		keySampleRate: 1,
+	}
 }
 
 func New(banner string, color, escapeNewline bool) *Logger {
-	return &Logger{defaultLogger, banner, color, escapeNewline}
+	return &Logger{logger: defaultLogger, view: newView(&settings{banner: banner, color: color, escapeNewline: escapeNewline})}
+}
+
+// NewDual 创建一个不与全局实例共享配置的实例,
+// 彩色文本输出到 console, 同时以 JSON 输出到 structured
+func NewDual(console, structured io.Writer) *Logger {
+	l := &Logger{logger: newLogger(console), view: newView(&settings{color: true})}
+	return l.AddJSONOutput(structured)
+}
+
+// Clone 返回一个配置独立的副本:
+//   - level, enabler 等共享配置, 以及 banner, color, escapeNewline 按值复制,
+//     此后双方各自的 Set* 互不影响
+//   - 输出按引用共享, 直到某一方调用 SetOutput/AddOutput 时才换成自己的输出,
+//     另一方不受影响 (写时复制)
+//   - 字段按值复制, 子实例追加字段不会影响父实例
+//   - Stats, FirstError, Counter 等统计, 以及写入超时与熔断的状态与父实例共享
+//
+// 由 New 创建的实例之间依旧共享全局配置, 只有 Clone 出的实例才拥有独立配置.
+func (l *Logger) Clone() *Logger {
+	c := *l
+	c.logger = l.logger.clone()
+	c.view = newView(l.settings())
+	c.fields = slices.Clip(l.fields)
+	c.providers = slices.Clip(l.providers)
+	return &c
+}
+
+// Reset 恢复为与 New(banner, false, false) 相同的默认设置, 仅保留 banner:
+//   - 实例自身的设置: color, escapeNewline, 字段与 provider, 格式 (JSON, lnav 等), 颜色方案等
+//   - 共享配置: level, 输出 (恢复为 os.Stderr, 含 JSON 输出与缓冲输出), filter, 写入与退出相关的设置,
+//     以及 Stats, FirstError, 计数器等统计
+//
+// 共享配置的重置作用于共享同一配置的所有实例. 重置前先执行 Close, 以落盘缓冲并停止定时汇总,
+// 出错时在重置后以 OnError 的默认方式输出. SetModuleLevel 的设置与 StartHeartbeat 不受影响
+func (l *Logger) Reset() *Logger {
+	err := l.Close()
+	fresh := newLogger(os.Stderr)
+	l.Lock()
+	fresh.Mutex = l.Mutex
+	*l.logger = *fresh
+	l.Unlock()
+	*l = Logger{logger: l.logger, view: newView(&settings{banner: l.settings().banner})}
+	if err != nil {
+		l.handleError(err)
+	}
+	return l
+}
+
+// WithField 返回附带一个字段的副本, 语义同 Clone
+func (l *Logger) WithField(key string, value any) *Logger {
+	c := l.Clone()
+	c.fields = append(c.fields, Any(key, value))
+	return c
+}
+
+// Merge 返回附带 other 的字段的副本, 语义同 Clone, l 与 other 均不受影响.
+// 结果先是 l 的全部字段, 再按原有顺序追加 other 中键不与 l 重复的字段, 即键冲突时以 l 为准;
+// 仅合并字段, other 的 banner, provider 等其他设置不会带入
+func (l *Logger) Merge(other *Logger) *Logger {
+	c := l.Clone()
+	for _, f := range other.fields {
+		if !slices.ContainsFunc(l.fields, func(lf Field) bool { return lf.Key == f.Key }) {
+			c.fields = append(c.fields, f)
+		}
+	}
+	return c
+}
+
+// AddFieldProvider 添加一个动态字段, fn 在每条确定输出的日志 (通过级别判断之后) 构造时调用一次,
+// 多个 provider 按添加顺序排在实例字段之后, 调用时传入的字段之前.
+// 每行都会调用并产生一次内存分配, fn 应当足够快, 不变的值请使用 WithField
+func (l *Logger) AddFieldProvider(fn func() (key string, value any)) *Logger {
+	l.providers = append(l.providers, fn)
+	return l
 }
 
 func (l *Logger) AddOutput(w io.Writer) *Logger {
-	l.Out = io.MultiWriter(l.Out, w)
+	l.Lock()
+	defer l.Unlock()
+	if mw, ok := l.Out.(multiWriter); ok {
+		l.Out = append(slices.Clip(mw), w)
+	} else {
+		l.Out = multiWriter{l.Out, w}
+	}
 	return l
 }
 
+// RemoveOutput 移除由 AddOutput 添加的 w, 移除唯一的输出后丢弃所有日志.
+// 对 AddBufferedOutput 添加的输出, 以及类型不可比较 (如函数适配器) 的 w 无效
+func (l *Logger) RemoveOutput(w io.Writer) *Logger {
+	l.Lock()
+	defer l.Unlock()
+	l.Out = removeWriter(l.Out, w)
+	return l
+}
+
+func removeWriter(out, w io.Writer) io.Writer {
+	mw, ok := out.(multiWriter)
+	if !ok {
+		if sameWriter(out, w) {
+			return io.Discard
+		}
+		return out
+	}
+	i := slices.IndexFunc(mw, func(o io.Writer) bool { return sameWriter(o, w) })
+	if i < 0 {
+		return out
+	}
+	rest := slices.Delete(slices.Clone(mw), i, i+1)
+	if len(rest) == 1 {
+		return rest[0]
+	}
+	return rest
+}
+
+// sameWriter 报告 a 与 b 是否为同一个输出, 类型不可比较时视为不同, 以免 == 时 panic
+func sameWriter(a, b io.Writer) bool {
+	va := reflect.ValueOf(a)
+	return va.IsValid() && va.Type() == reflect.TypeOf(b) && va.Comparable() && a == b
+}
+
+// outputRef 以指针标识类型不可比较的输出, 以便 WithOutputDuring 结束后将其移除
+type outputRef struct{ w io.Writer }
+
+func (r *outputRef) Write(p []byte) (int, error) { return r.w.Write(p) }
+
+func (r *outputRef) WriteLevel(level Level, p []byte) (int, error) { return writeLevel(r.w, level, p) }
+
+func (r *outputRef) Sync() error { return syncOutputs(r.w) }
+
+// WithOutputDuring 在 fn 执行期间将 w 作为额外的输出, 结束后 (含 panic) 移除.
+// 与 CaptureDuring 一样作用于共享同一配置的所有实例
+func (l *Logger) WithOutputDuring(w io.Writer, fn func()) {
+	if !reflect.ValueOf(w).Comparable() {
+		w = &outputRef{w}
+	}
+	l.AddOutput(w)
+	defer l.RemoveOutput(w)
+	fn()
+}
+
 func (l *Logger) SetOutput(w io.Writer) *Logger {
 	l.Out = w
 	return l
 }
 
+// SwapOutput 在一次加锁内将输出替换为 w 并返回原输出, 可与日志调用并发执行,
+// 用于临时重定向 (如 SimpleLogtest), 之后以原输出再次调用即可恢复.
+// 作用于共享同一配置的所有实例
+func (l *Logger) SwapOutput(w io.Writer) (prev io.Writer) {
+	l.Lock()
+	defer l.Unlock()
+	prev, l.Out = l.Out, w
+	return prev
+}
+
+// CaptureDuring 在 fn 执行期间将输出重定向到内部缓冲, 结束后 (含 panic) 恢复原输出,
+// 返回期间写入的全部内容.
+// 重定向作用于共享同一配置的所有实例, 期间其他 goroutine 经由这些实例输出的日志也会被捕获.
+func (l *Logger) CaptureDuring(fn func()) []byte {
+	buf := new(bytes.Buffer)
+	prev := l.SwapOutput(buf) // 写入总在锁内进行, buf 无需额外同步
+	defer l.SwapOutput(prev)
+	fn()
+	l.Lock()
+	defer l.Unlock()
+	return bytes.Clone(buf.Bytes())
+}
+
 func (l *Logger) SetLevel(level Level) *Logger {
 	l.Level = level
+	l.live.level.Store(int64(level))
+	return l
+}
+
+// SetEnabler 设置自定义的级别判断函数, 非 nil 时取代 level >= Level 的比较,
+// 传入 nil 恢复默认行为.
+// 每次日志调用都会执行一次, 务必保证其足够快.
+func (l *Logger) SetEnabler(enabler func(level Level, banner string) bool) *Logger {
+	l.enabler = enabler
+	return l
+}
+
+// SetShowGap 距上一行日志的间隔超过 threshold 时,
+// 在消息前加上 "+1.2s" 形式的标记, 便于发现停顿, 传入 0 关闭
+func (l *Logger) SetShowGap(threshold time.Duration) *Logger {
+	l.gap = threshold
+	return l
+}
+
+// SetDurationPrecision 输出前将 Dur 字段及 time.Duration 类型的 Any 字段舍入到 d 的整数倍,
+// 如 time.Millisecond 时 12.3847ms 输出为 12ms, 传入 0 关闭
+func (l *Logger) SetDurationPrecision(d time.Duration) *Logger {
+	l.durPrecision = d
 	return l
 }
 
+// SetSubsecondDigits 设置同一天内时间戳的小数位数, 默认为 3 (毫秒), 0 表示不显示小数.
+// 超出 [0, 9] 时保持不变, 并调用 OnError
+func (l *Logger) SetSubsecondDigits(n int) *Logger {
+	if n < 0 || n > 9 {
+		l.handleError(fmt.Errorf("SimpleLog: subsecond digits %d out of range [0, 9]", n))
+		return l
+	}
+	l.sameDayLayout = "[15:04:05"
+	if n > 0 {
+		l.sameDayLayout += "." + strings.Repeat("0", n)
+	}
+	l.sameDayLayout += "]"
+	return l
+}
+
+// SetLevelTimeFormat 为 level 单独设置时间戳格式 (time.Layout), 取代默认的自适应格式,
+// 如对高频的 DebugLevel 使用 "[05.000]", 传入 "" 恢复默认.
+// 设置后每行多一次 map 查找; 自定义格式不参与日期变化的判断, 也不受 SetSubsecondDigits 影响
+func (l *Logger) SetLevelTimeFormat(level Level, layout string) *Logger {
+	formats := maps.Clone(l.levelTimeFormats) // 写时复制, 不影响 Clone 出的其他实例
+	if layout == "" {
+		delete(formats, level)
+	} else {
+		if formats == nil {
+			formats = make(map[Level]string)
+		}
+		formats[level] = layout
+	}
+	if len(formats) == 0 {
+		formats = nil
+	}
+	l.levelTimeFormats = formats
+	return l
+}
+
+// SetFilter 设置事件过滤器, 在级别判断之后, 格式化之前执行,
+// 可就地修改 e (增删字段, 改写消息等), 返回 false 则丢弃该事件, 传入 nil 取消.
+// 每条通过级别判断的日志都会执行一次.
+func (l *Logger) SetFilter(filter func(e *Event) bool) *Logger {
+	l.filter = filter
+	return l
+}
+
+var ErrInvalidBanner = errors.New("SimpleLog: banner contains non-printable characters")
+
+// SetBanner 设置 banner, 自动补全两侧的方括号.
+// banner 只允许可打印字符 (unicode.IsPrint, 含空格),
+// 含换行, ANSI 转义等控制字符或非法 UTF-8 时保持原 banner 不变, 并以 ErrInvalidBanner 调用 OnError
 func (l *Logger) SetBanner(banner string) *Logger {
+	if err := l.SetBannerChecked(banner); err != nil {
+		l.handleError(err)
+	}
+	return l
+}
+
+// SetBannerChecked 同 SetBanner, 但以返回值报告非法的 banner
+func (l *Logger) SetBannerChecked(banner string) error {
+	banner, err := normalizeBanner(banner)
+	if err != nil {
+		return err
+	}
+	l.update(func(s *settings) { s.banner = banner })
+	return nil
+}
+
+// normalizeBanner 检查 banner 并补全两侧的方括号
+func normalizeBanner(banner string) (string, error) {
+	if !utf8.ValidString(banner) || strings.ContainsFunc(banner, func(r rune) bool { return !unicode.IsPrint(r) }) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidBanner, banner)
+	}
 	if len(banner) > 0 && banner[0] != '[' {
 		banner = "[" + banner
 	}
 	if len(banner) > 0 && banner[len(banner)-1] != ']' {
 		banner = banner + "]"
 	}
-	l.banner = banner
+	return banner, nil
+}
+
+// SetGutter 在每行行首加上按级别着色的标记 (见 LevelGutterC), 便于快速浏览,
+// 仅在开启颜色时生效, 默认关闭
+func (l *Logger) SetGutter(gutter bool) *Logger {
+	l.gutter = gutter
+	return l
+}
+
+// SetTimestampColor 设置时间戳的颜色, code 为 SGR 参数, 如 "2", "90", "1;36",
+// 为空则不着色, 仅在开启颜色时生效
+func (l *Logger) SetTimestampColor(code string) *Logger {
+	l.timeColor = code
+	return l
+}
+
+// SetBannerColor 设置 banner 的颜色, 用法同 SetTimestampColor
+func (l *Logger) SetBannerColor(code string) *Logger {
+	l.bannerColor = code
+	return l
+}
+
+// SetColorFunc 开启颜色时, 以 fn 返回的 prefix 与 suffix 包裹级别标签, 取代 LevelBannerC,
+// 如对重复的错误使用更醒目的颜色. 每输出一行调用一次, 传入 nil 恢复默认
+func (l *Logger) SetColorFunc(fn func(level Level, msg string) (prefix, suffix string)) *Logger {
+	l.colorFunc = fn
+	return l
+}
+
+// SetFullJSON 以 JSON 输出包括调用处在内的全部信息, 每行一个对象, 键见 appendJSON
+func (l *Logger) SetFullJSON(full bool) *Logger {
+	l.update(func(s *settings) { s.fullJSON = full })
+	return l
+}
+
+// SetSkipEmpty 为 true 时, 消息为空且没有任何字段的日志不会输出
+func (l *Logger) SetSkipEmpty(skip bool) *Logger {
+	l.skipEmpty = skip
 	return l
 }
 
 func (l *Logger) SetEscapeNewline(escape bool) *Logger {
-	l.escapeNewline = escape
+	l.update(func(s *settings) { s.escapeNewline = escape })
 	return l
 }
 
 var (
-	lastLogoutMonth int // 新的一月时输出一次带月份的日志
-	lastLogoutDay   int // 新的一天时输出一次带日期的日志
+	lastLogoutDate atomic.Int32 // 月*32+日, 新的一月/一天时输出一次带月份/日期的日志
+	lastLineTime   atomic.Int64 // 上一行日志的时间 (UnixNano), 用于 SetShowGap
 )
 
-func (l *logger) formatTime() string {
-	t := time.Now()
-	month, day := int(t.Month()), t.Day()
-	defer func() {
-		lastLogoutMonth, lastLogoutDay = month, day
-	}()
-	if month != lastLogoutMonth {
-		return t.Format("[15:04-|01/02]")
-	} else if day != lastLogoutDay {
-		return t.Format("[15:04:05-|02]")
+func (l *logger) timeNow() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// appendTime peek 为 true 时只读取上一行的日期, 不更新, 用于不输出的格式化
+func (l *logger) appendTime(dst []byte, t time.Time, level Level, peek bool) []byte {
+	if layout, ok := l.levelTimeFormats[level]; ok {
+		return t.AppendFormat(dst, layout)
+	}
+	month, day := int32(t.Month()), int32(t.Day())
+	var last int32
+	if peek {
+		last = lastLogoutDate.Load()
+	} else {
+		last = lastLogoutDate.Swap(month*32 + day)
+	}
+	if month != last/32 {
+		return t.AppendFormat(dst, "[15:04-|01/02]")
+	} else if day != last%32 {
+		return t.AppendFormat(dst, "[15:04:05-|02]")
+	} else {
+		return t.AppendFormat(dst, l.sameDayLayout)
+	}
+}
+
+// appendGap 距上一行超过阈值时追加 " +<间隔>", peek 同 appendTime
+func (l *logger) appendGap(dst []byte, t time.Time, peek bool) []byte {
+	var last int64
+	if peek {
+		last = lastLineTime.Load()
 	} else {
-		return t.Format("[15:04:05.000]")
+		last = lastLineTime.Swap(t.UnixNano())
 	}
+	if l.gap <= 0 || last == 0 {
+		return dst
+	}
+	gap := time.Duration(t.UnixNano() - last)
+	if gap <= l.gap {
+		return dst
+	}
+	if gap >= time.Second {
+		gap = gap.Round(100 * time.Millisecond)
+	} else {
+		gap = gap.Round(time.Millisecond)
+	}
+	dst = append(dst, " +"...)
+	return append(dst, gap.String()...)
 }
 
-var newLineReplacer = strings.NewReplacer("\n", "\x1b[97m\\n\x1b[m")
+const escapedNewline = "\x1b[97m\\n\x1b[m"
+
+// appendEscapeNewline 将 s 中的换行符替换为高亮的 \n 后追加
+func appendEscapeNewline(dst []byte, s string) []byte {
+	for {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			return append(dst, s...)
+		}
+		dst = append(dst, s[:i]...)
+		dst = append(dst, escapedNewline...)
+		s = s[i+1:]
+	}
+}
 
-func (l *Logger) Format(level Level, s string) string {
-	if l.escapeNewline {
-		s = newLineReplacer.Replace(s)
+var bufPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+// Event 一条日志的全部信息
+type Event struct {
+	Time    time.Time
+	Level   Level
+	Banner  string
+	Message string
+	Fields  []Field // 实例字段在前, 调用时传入的字段在后
+	Caller  Caller  // 仅在输出 JSON 时采集
+}
+
+// Caller 日志调用处
+type Caller struct {
+	File string
+	Line int
+	Func string
+}
+
+// newEvent 以设置 cfg 构造一条日志, t 为零值时使用当前时间
+func (l *Logger) newEvent(cfg *settings, t time.Time, level Level, s string, fields []Field) Event {
+	if t.IsZero() {
+		t = l.timeNow()
 	}
-	var lvl string
-	if l.color {
-		lvl = LevelBannerC[level]
+	e := Event{
+		Time:    t,
+		Level:   level,
+		Banner:  cfg.banner,
+		Message: s,
+		Fields:  fields,
+	}
+	if len(l.providers) > 0 {
+		e.Fields = l.providedFields(fields)
+	} else if len(l.fields) > 0 {
+		e.Fields = append(slices.Clip(l.fields), fields...)
+	}
+	if l.showDepth {
+		e.Fields = append(slices.Clip(e.Fields), Int("depth", stackDepth()))
+	}
+	if cfg.fullJSON || l.live.json.Load() {
+		e.Caller = caller()
+	}
+	return e
+}
+
+// providedFields 合并实例字段, provider 字段与调用时传入的字段
+func (l *Logger) providedFields(fields []Field) []Field {
+	merged := make([]Field, 0, len(l.fields)+len(l.providers)+len(fields))
+	merged = append(merged, l.fields...)
+	for _, fn := range l.providers {
+		merged = append(merged, Any(fn()))
+	}
+	return append(merged, fields...)
+}
+
+// appendLine 以设置 cfg 将完整的一行日志追加到 dst
+func (l *Logger) appendLine(cfg *settings, dst []byte, level Level, s string, fields []Field) []byte {
+	e := l.newEvent(cfg, time.Time{}, level, s, fields)
+	return l.appendEvent(cfg, dst, &e, nil)
+}
+
+// appendEvent 文本格式下 stack 原样追加在该行之后, JSON 格式下作为 stack 键
+func (l *Logger) appendEvent(cfg *settings, dst []byte, e *Event, stack []byte) []byte {
+	if cfg.fullJSON {
+		return l.appendJSON(dst, e, stack)
+	}
+	return append(l.appendText(cfg, dst, e), stack...)
+}
+
+func (l *Logger) appendText(cfg *settings, dst []byte, e *Event) []byte {
+	if l.lnav {
+		return l.appendLnav(cfg, dst, e)
+	}
+	if cfg.color && l.gutter {
+		if l.customColors() {
+			dst = l.appendLevelColored(dst, e.Level, levelGutterN(e.Level))
+		} else {
+			dst = append(dst, LevelGutterC[e.Level]...)
+		}
+	}
+	if cfg.color && l.colorFunc != nil {
+		// 复制消息, 以免 e 的内容经由 colorFunc 逃逸到堆上
+		prefix, suffix := l.colorFunc(e.Level, strings.Clone(e.Message))
+		dst = append(dst, prefix...)
+		dst = append(dst, LevelBannerN[e.Level]...)
+		dst = append(dst, suffix...)
+	} else if cfg.color && l.customColors() {
+		dst = l.appendLevelColored(dst, e.Level, LevelBannerN[e.Level])
+	} else if cfg.color {
+		dst = append(dst, LevelBannerC[e.Level]...)
 	} else {
-		lvl = LevelBannerN[level]
+		dst = append(dst, LevelBannerN[e.Level]...)
+	}
+	dst = l.appendColored(cfg, dst, l.timeColor, func(dst []byte) []byte {
+		return l.appendTime(dst, e.Time, e.Level, cfg.peek)
+	})
+	dst = l.appendColored(cfg, dst, l.bannerColor, func(dst []byte) []byte {
+		return append(dst, e.Banner...)
+	})
+	dst = l.appendGap(dst, e.Time, cfg.peek)
+	return l.appendBody(cfg, dst, e)
+}
+
+// appendBody 追加消息与字段, 以换行结尾
+func (l *Logger) appendBody(cfg *settings, dst []byte, e *Event) []byte {
+	if len(e.Message) > 0 {
+		dst = append(dst, ' ')
+		if cfg.escapeNewline {
+			dst = appendEscapeNewline(dst, e.Message)
+		} else {
+			dst = append(dst, e.Message...)
+		}
 	}
-	t := l.formatTime()
-	sb := new(strings.Builder)
-	sb.Grow(len(lvl) + len(t) + len(l.banner) + len(s) + 2)
-	sb.WriteString(lvl)
-	sb.WriteString(t)
-	sb.WriteString(l.banner)
-	sb.WriteByte(' ')
-	sb.WriteString(s)
-	sb.WriteByte('\n')
-	return sb.String()
+	for i := range e.Fields {
+		if e.Fields[i].scope == ScopeJSONOnly {
+			continue
+		}
+		dst = append(dst, ' ')
+		if f := &e.Fields[i]; l.nilText != "" && f.kind == anyKind && isNil(f.val) {
+			dst = append(dst, f.Key...)
+			dst = append(dst, '=')
+			dst = append(dst, l.nilText...)
+		} else {
+			dst = f.appendText(dst, l.quoting)
+		}
+	}
+	return append(dst, '\n')
+}
+
+// appendColored 开启颜色且 code 非空时, 以 code 包裹 appendFn 追加的内容
+func (l *Logger) appendColored(cfg *settings, dst []byte, code string, appendFn func([]byte) []byte) []byte {
+	if !cfg.color || code == "" {
+		return appendFn(dst)
+	}
+	dst = append(dst, "\x1b["...)
+	dst = append(dst, code...)
+	dst = append(dst, 'm')
+	dst = appendFn(dst)
+	return append(dst, l.ansiResetCode()...)
+}
+
+// Format 返回格式化后的一行, 不会输出, 也不影响之后输出的行的日期与间隔标记
+func (l *Logger) Format(level Level, s string) string {
+	return string(l.appendLine(l.peekSettings(), nil, level, s, nil))
+}
+
+// Render 同 Format, 但不带末尾的换行符, color 为 false 时不带颜色 (不影响 l 自身的设置),
+// 用于将日志嵌入报告等较大的文档
+func (l *Logger) Render(level Level, s string, color bool) string {
+	cfg := l.peekSettings()
+	cfg.color = color
+	return strings.TrimSuffix(string(l.appendLine(cfg, nil, level, s, nil)), "\n")
 }
 
+// peekSettings 返回当前设置的副本, 用于只格式化而不输出的调用方
+func (l *Logger) peekSettings() *settings {
+	cfg := *l.settings()
+	cfg.peek = true
+	return &cfg
+}
+
+// Output 原样输出 s, 视为最低级别
 func (l *Logger) Output(s string) {
-	l.Lock()
-	defer l.Unlock()
-	l.Out.Write([]byte(s))
+	l.write(TraceLevel, []byte(s), nil)
+}
+
+// log 格式化并输出一行日志, 不做级别判断
+func (l *Logger) log(level Level, s string, fields []Field) {
+	l.logAt(time.Time{}, level, s, fields)
+}
+
+// logAt 同 log, t 为零值时使用当前时间
+func (l *Logger) logAt(t time.Time, level Level, s string, fields []Field) {
+	if l.nop { // Print, Printf 等不经过 levelOk 的入口
+		return
+	}
+	if l.skipEmpty && len(s) == 0 && len(fields) == 0 && len(l.fields) == 0 && len(l.providers) == 0 {
+		return
+	}
+	if l.filter != nil {
+		l.logFiltered(t, level, s, fields)
+		return
+	}
+	cfg := l.settings()
+	e := l.newEvent(cfg, t, level, s, fields)
+	l.emit(cfg, &e, nil)
+}
+
+// logFiltered 复制字段后交由 filter 处理, 以便 filter 就地修改而不影响实例字段;
+// 与 log 分开, 以免没有 filter 时 e 与 fields 逃逸到堆上
+func (l *Logger) logFiltered(t time.Time, level Level, s string, fields []Field) {
+	cfg := l.settings()
+	e := l.newEvent(cfg, t, level, s, nil)
+	e.Fields = append(slices.Clone(e.Fields), fields...)
+	if l.filter(&e) {
+		l.emit(cfg, &e, nil)
+	}
 }
 
+// filterEvent 复制字段后执行 filter, 返回 false 表示丢弃
+func (l *Logger) filterEvent(e *Event) bool {
+	if l.filter == nil {
+		return true
+	}
+	e.Fields = slices.Clone(e.Fields)
+	return l.filter(e)
+}
+
+// emit 以设置 cfg 格式化并输出 e, stack 非空时附带调用栈
+func (l *Logger) emit(cfg *settings, e *Event, stack []byte) {
+	if needResolve(e.Fields, l.durPrecision) {
+		resolved := *e // 不直接写回 *e, 以免 e.Fields 逃逸到堆上
+		resolved.Fields = resolveFields(e.Fields, l.durPrecision)
+		e = &resolved
+	}
+	l.recordLine(e.Level, e.Message, e.Time.UnixNano())
+	bp := bufPool.Get().(*[]byte)
+	b := l.appendEvent(cfg, (*bp)[:0], e, stack)
+	if !l.live.json.Load() {
+		l.write(e.Level, b, nil)
+	} else {
+		jbp := bufPool.Get().(*[]byte)
+		jb := l.appendJSON((*jbp)[:0], e, stack)
+		l.write(e.Level, b, jb)
+		*jbp = jb
+		bufPool.Put(jbp)
+	}
+	*bp = b
+	bufPool.Put(bp)
+}
+
+// Block 将多行作为一个整体输出, 每行带有完整的前缀.
+// 所有行拼接后在一次加锁内写入, 期间其他 goroutine 的日志不会插入其中,
+// 锁会一直持有到全部写完, 因此不宜一次输出过多内容
+func (l *Logger) Block(level Level, lines ...string) {
+	if !l.levelOk(level) {
+		return
+	}
+	var b, jb []byte
+	cfg, jsonOn := l.settings(), l.live.json.Load()
+	for _, s := range lines {
+		e, ok := l.blockEvent(cfg, level, s, nil)
+		if !ok {
+			continue
+		}
+		l.recordLine(level, e.Message, e.Time.UnixNano())
+		b = l.appendEvent(cfg, b, &e, nil)
+		if jsonOn {
+			jb = l.appendJSON(jb, &e, nil)
+		}
+	}
+	if len(b) > 0 {
+		l.write(level, b, jb)
+	}
+}
+
+// blockEvent 构造多行输出中的一行, 已执行 filter 并解析字段, 返回 false 表示丢弃
+func (l *Logger) blockEvent(cfg *settings, level Level, s string, fields []Field) (Event, bool) {
+	e := l.newEvent(cfg, time.Time{}, level, s, fields)
+	if !l.filterEvent(&e) {
+		return e, false
+	}
+	if needResolve(e.Fields, l.durPrecision) {
+		e.Fields = resolveFields(e.Fields, l.durPrecision)
+	}
+	return e, true
+}
+
+// Print ErrorLevel 的参数全部为 nil 时不输出, 同 Error
 func (l *Logger) Print(level Level, a ...any) {
-	l.Output(l.Format(level, fmt.Sprint(a...)))
+	if s, ok := l.sprint(level, a); ok {
+		l.log(level, s, nil)
+	}
 }
 
 func (l *Logger) Printf(level Level, format string, a ...any) {
-	l.Output(l.Format(level, fmt.Sprintf(format, a...)))
+	l.log(level, fmt.Sprintf(format, a...), nil)
+}
+
+// LogAt 以 t 作为日志时间输出, 用于回放历史事件等场景, 会进行级别判断
+func (l *Logger) LogAt(t time.Time, level Level, a ...any) {
+	if !l.levelOk(level) {
+		return
+	}
+	if s, ok := l.sprint(level, a); ok {
+		l.logAt(t, level, s, nil)
+	}
+}
+
+func (l *Logger) LogAtf(t time.Time, level Level, format string, a ...any) {
+	if !l.levelOk(level) {
+		return
+	}
+	l.logAt(t, level, fmt.Sprintf(format, a...), nil)
 }
 
 func (l *Logger) levelOk(level Level) bool {
-	return level >= l.Level // 大于等于则输出
+	if l.nop {
+		return false
+	}
+	if l.enabler != nil {
+		return l.enabler(level, l.settings().banner)
+	}
+	if min, ok := moduleLevel(l.settings().banner); ok {
+		return level >= min
+	}
+	return level >= Level(l.live.level.Load()) // 大于等于则输出
 }
 
 func (l *Logger) Trace(a ...any) {
@@ -216,6 +926,7 @@ func (l *Logger) Warnf(format string, a ...any) {
 	l.Printf(WarnLevel, format, a...)
 }
 
+// Error 参数全部为 nil 时不输出, 因此可以直接 Error(err)
 func (l *Logger) Error(a ...any) {
 	if !l.levelOk(ErrorLevel) {
 		return
@@ -234,16 +945,14 @@ func (l *Logger) Fatal(a ...any) {
 	if !l.levelOk(FatalLevel) {
 		return
 	}
-	l.Print(FatalLevel, a...)
-	os.Exit(1)
+	l.fatal(func() { l.Print(FatalLevel, a...) })
 }
 
 func (l *Logger) Fatalf(format string, a ...any) {
 	if !l.levelOk(FatalLevel) {
 		return
 	}
-	l.Printf(FatalLevel, format, a...)
-	os.Exit(1)
+	l.fatal(func() { l.Printf(FatalLevel, format, a...) })
 }
 
 func (l *Logger) Panic(a ...any) {
@@ -268,7 +977,7 @@ func (l *Logger) FakePanic(a ...any) {
 		return
 	}
 	l.Print(PanicLevel, a...)
-	l.Output(string(debug.Stack()))
+	l.Output(string(limitStack(debug.Stack(), l.maxStackDepth)))
 }
 
 // FakePanic only print stack
@@ -277,5 +986,5 @@ func (l *Logger) FakePanicf(format string, a ...any) {
 		return
 	}
 	l.Printf(PanicLevel, format, a...)
-	l.Output(string(debug.Stack()))
+	l.Output(string(limitStack(debug.Stack(), l.maxStackDepth)))
 }
@@ -0,0 +1,62 @@
+package SimpleLogtest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Miuzarte/SimpleLog"
+)
+
+// recordTB 记录 Errorf 而不使外层测试失败
+type recordTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordTB) Helper() {}
+
+func (r *recordTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestExpectNoLogsAbove(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := SimpleLog.New("", false, false).Clone().SetOutput(buf).SetBanner("hygiene")
+
+	t.Run("clean", func(t *testing.T) {
+		tb := &recordTB{TB: t}
+		done := ExpectNoLogsAbove(tb, l, SimpleLog.InfoLevel)
+		l.Debug("fine")
+		l.Info("also fine")
+		done()
+		if len(tb.errors) != 0 {
+			t.Fatalf("unexpected failures: %q", tb.errors)
+		}
+	})
+
+	t.Run("warned", func(t *testing.T) {
+		tb := &recordTB{TB: t}
+		done := ExpectNoLogsAbove(tb, l, SimpleLog.InfoLevel)
+		l.Info("fine")
+		l.Warn("disk almost full")
+		l.Block(SimpleLog.ErrorLevel, "a", "b")
+		done()
+		if len(tb.errors) != 1 {
+			t.Fatalf("want 1 failure, got %q", tb.errors)
+		}
+		msg := tb.errors[0]
+		if !strings.Contains(msg, "disk almost full") || !strings.Contains(msg, "[hygiene] b") || strings.Contains(msg, "fine") {
+			t.Fatalf("failure does not list offending lines: %q", msg)
+		}
+	})
+
+	if buf.Len() != 0 {
+		t.Fatalf("captured lines leaked to original output: %q", buf.String())
+	}
+	l.Info("restored")
+	if !strings.Contains(buf.String(), "restored") {
+		t.Fatal("original output not restored")
+	}
+}
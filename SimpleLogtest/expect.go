@@ -0,0 +1,58 @@
+// Package SimpleLogtest 测试中使用的 SimpleLog 辅助函数, 单独成包以免库本身链接 testing
+package SimpleLogtest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Miuzarte/SimpleLog"
+)
+
+type levelLine struct {
+	level SimpleLog.Level
+	line  string
+}
+
+// levelRecorder 记录每次写入及其级别
+type levelRecorder struct {
+	mu    sync.Mutex
+	lines []levelLine
+}
+
+func (r *levelRecorder) Write(p []byte) (int, error) {
+	return r.WriteLevel(SimpleLog.TraceLevel, p)
+}
+
+func (r *levelRecorder) WriteLevel(level SimpleLog.Level, p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, levelLine{level, string(p)})
+	return len(p), nil
+}
+
+// ExpectNoLogsAbove 将 l 的输出替换为记录器, 调用返回的 done 时恢复原输出,
+// 若期间有高于 max 的日志则调用 t.Errorf 并列出这些日志.
+// 与 CaptureDuring 相同, 替换作用于共享同一配置的所有实例
+//
+//	defer SimpleLogtest.ExpectNoLogsAbove(t, logger, SimpleLog.InfoLevel)()
+func ExpectNoLogsAbove(t testing.TB, l *SimpleLog.Logger, max SimpleLog.Level) (done func()) {
+	t.Helper()
+	rec := new(levelRecorder)
+	prev := l.SwapOutput(rec)
+	return func() {
+		t.Helper()
+		l.SwapOutput(prev)
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		var sb strings.Builder
+		for _, ll := range rec.lines {
+			if ll.level > max {
+				sb.WriteString(ll.line)
+			}
+		}
+		if sb.Len() > 0 {
+			t.Errorf("unexpected logs above %s:\n%s", max, sb.String())
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	file := new(bytes.Buffer)
+	aw := NewAuditWriter(file, "")
+	l := newTestLogger(io.Discard, "audit").AddJSONOutput(aw)
+	l.Info("login", "alice")
+	l.Block(WarnLevel, "grant", "revoke")
+	if err := VerifyAuditLog(bytes.NewReader(file.Bytes())); err != nil {
+		t.Fatalf("valid chain rejected: %v\n%s", err, file.String())
+	}
+
+	// 重启后续写
+	last, err := LastAuditHash(bytes.NewReader(file.Bytes()))
+	if err != nil || last != aw.LastHash() || len(last) != 64 {
+		t.Fatalf("LastAuditHash() = %q, %v, want %q", last, err, aw.LastHash())
+	}
+	l = newTestLogger(io.Discard, "audit").AddJSONOutput(NewAuditWriter(file, last))
+	l.Info("logout")
+	if err := VerifyAuditLog(bytes.NewReader(file.Bytes())); err != nil {
+		t.Fatalf("resumed chain rejected: %v", err)
+	}
+
+	lines := strings.SplitAfter(file.String(), "\n")
+	for name, tampered := range map[string]string{
+		"modified": strings.Replace(file.String(), "revoke", "revokE", 1),
+		"deleted":  lines[0] + lines[2] + lines[3] + lines[4],
+		"swapped":  lines[0] + lines[2] + lines[1] + lines[3] + lines[4],
+	} {
+		if err := VerifyAuditLog(strings.NewReader(tampered)); !errors.Is(err, ErrAuditChain) {
+			t.Errorf("%s entry: VerifyAuditLog() = %v, want ErrAuditChain", name, err)
+		}
+	}
+
+	// 未接上的续写
+	broken := bytes.NewBuffer(bytes.Clone(file.Bytes()))
+	NewAuditWriter(broken, "").Write([]byte(`{"msg":"x"}` + "\n"))
+	if err := VerifyAuditLog(broken); !errors.Is(err, ErrAuditChain) {
+		t.Fatalf("restart without the last hash: %v", err)
+	}
+
+	if _, err := aw.Write([]byte("plain text\n")); !errors.Is(err, ErrAuditFormat) {
+		t.Fatalf("non-JSON line: %v", err)
+	}
+}
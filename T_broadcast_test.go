@@ -0,0 +1,74 @@
+package SimpleLog
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster(100)
+	l := newTestLogger(io.Discard, "live").AddOutput(b)
+
+	ch1, unsub1 := b.Subscribe()
+	ch2, unsub2 := b.Subscribe()
+	defer unsub2()
+
+	var wg sync.WaitGroup
+	var got1, got2 []string
+	left := make(chan struct{})
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for line := range ch1 {
+			got1 = append(got1, string(line))
+			if len(got1) == 3 {
+				unsub1()
+				close(left)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for line := range ch2 {
+			got2 = append(got2, string(line))
+			if len(got2) == 10 {
+				return
+			}
+		}
+	}()
+	for i := range 10 {
+		if i == 3 {
+			<-left
+		}
+		l.Info("line " + strconv.Itoa(i))
+	}
+	wg.Wait()
+	unsub1() // 重复调用无影响
+
+	if len(got1) != 3 || !strings.HasSuffix(got1[2], "line 2\n") {
+		t.Errorf("subscriber 1 got %q", got1)
+	}
+	if len(got2) != 10 || !strings.HasSuffix(got2[9], "line 9\n") {
+		t.Errorf("subscriber 2 got %q", got2)
+	}
+	if _, ok := <-ch1; ok {
+		t.Error("channel not closed after unsubscribe")
+	}
+}
+
+func TestBroadcasterDrop(t *testing.T) {
+	b := NewBroadcaster(2)
+	l := newTestLogger(b, "")
+	ch, unsub := b.Subscribe()
+	defer unsub()
+	for range 5 {
+		l.Info("x")
+	}
+	if len(ch) != 2 || b.Dropped() != 3 {
+		t.Fatalf("buffered %d, dropped %d, want 2 and 3", len(ch), b.Dropped())
+	}
+}
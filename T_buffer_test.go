@@ -0,0 +1,229 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer 可被定时落盘的 goroutine 并发写入
+type syncBuffer struct {
+	mu sync.Mutex
+	bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Buffer.String()
+}
+
+func TestBufferedOutput(t *testing.T) {
+	direct := new(bytes.Buffer)
+	sized := new(bytes.Buffer)
+	timed := new(syncBuffer)
+	l := newTestLogger(io.Discard, "buf").
+		AddBufferedOutput(direct, Unbuffered).
+		AddBufferedOutput(sized, BufferedSize(128)).
+		AddBufferedOutput(timed, BufferedInterval(10*time.Millisecond))
+
+	l.Info("first")
+	if !strings.Contains(direct.String(), "first") {
+		t.Fatalf("unbuffered output not written immediately: %q", direct.String())
+	}
+	if sized.Len() != 0 {
+		t.Fatalf("size-buffered output written before full: %q", sized.String())
+	}
+
+	// 写满 128 字节后应落盘
+	for range 4 {
+		l.Info(strings.Repeat("x", 40))
+	}
+	if !strings.Contains(sized.String(), "first") {
+		t.Fatalf("size-buffered output not flushed when full: %q", sized.String())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(timed.String(), "first") {
+		if time.Now().After(deadline) {
+			t.Fatal("interval-buffered output not flushed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	l.Info("last")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, out := range []string{sized.String(), timed.String()} {
+		if !strings.HasSuffix(out, "last\n") {
+			t.Fatalf("Close did not flush: %q", out)
+		}
+	}
+}
+
+// countSyncer 记录 Sync 调用次数
+type countSyncer struct {
+	bytes.Buffer
+	syncs int
+}
+
+func (w *countSyncer) Sync() error {
+	w.syncs++
+	return nil
+}
+
+func TestSyncLevel(t *testing.T) {
+	file := new(countSyncer)
+	var errs []error
+	l := newTestLogger(io.Discard, "sync").
+		AddBufferedOutput(file, BufferedSize(4096)).
+		AddOutput(os.Stderr). // 不支持 Sync 的输出不应报错
+		SetOnError(func(err error) { errs = append(errs, err) }).
+		SetSyncLevel(ErrorLevel)
+
+	l.Info("batched")
+	if file.Len() != 0 || file.syncs != 0 {
+		t.Fatalf("info line bypassed the buffer: %q, %d syncs", file.String(), file.syncs)
+	}
+	l.Error("critical")
+	if out := file.String(); !strings.Contains(out, "batched") || !strings.HasSuffix(out, "critical\n") {
+		t.Fatalf("error line not written synchronously: %q", out)
+	}
+	if file.syncs != 1 {
+		t.Fatalf("Sync called %d times, want 1", file.syncs)
+	}
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	sized := new(bytes.Buffer)
+	l := newTestLogger(io.Discard, "main").AddBufferedOutput(sized, BufferedSize(4096))
+	l.Info("pending")
+	Shutdown()
+	if !strings.Contains(sized.String(), "pending") {
+		t.Fatalf("Shutdown did not flush: %q", sized.String())
+	}
+
+	stderr := new(bytes.Buffer)
+	defaultLogger.Lock()
+	stderr0 := defaultLogger.Out
+	defaultLogger.Out = stderr
+	defaultLogger.Unlock()
+	defer func() {
+		defaultLogger.Lock()
+		defaultLogger.Out = stderr0
+		defaultLogger.Unlock()
+	}()
+
+	sized.Reset()
+	l = newTestLogger(io.Discard, "main").AddBufferedOutput(sized, BufferedSize(4096))
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		defer Shutdown()
+		l.Info("before panic")
+		panic("boom")
+	}()
+	if recovered != "boom" {
+		t.Fatalf("panic not re-raised: %v", recovered)
+	}
+	if !strings.Contains(sized.String(), "before panic") {
+		t.Fatalf("Shutdown did not flush while panicking: %q", sized.String())
+	}
+	if !strings.Contains(stderr.String(), "panic: boom") || !strings.Contains(stderr.String(), "TestShutdown") {
+		t.Fatalf("panic not logged with stack: %q", stderr.String())
+	}
+}
+
+// writeRecorder 记录每次写入底层输出的内容
+type writeRecorder struct {
+	writes []string
+}
+
+func (r *writeRecorder) Write(p []byte) (int, error) {
+	r.writes = append(r.writes, string(p))
+	return len(p), nil
+}
+
+func TestBufferOversize(t *testing.T) {
+	rec := new(writeRecorder)
+	l := newTestLogger(io.Discard, "").AddBufferedOutput(rec, BufferedSize(64))
+	l.Info("small")
+	l.Info(strings.Repeat("x", 100))
+	l.Info("tail")
+	l.Flush()
+
+	if len(rec.writes) != 3 || !strings.HasSuffix(rec.writes[0], "small\n") ||
+		!strings.HasSuffix(rec.writes[1], strings.Repeat("x", 100)+"\n") || !strings.HasSuffix(rec.writes[2], "tail\n") {
+		t.Fatalf("direct policy: unexpected writes %q", rec.writes)
+	}
+
+	rec = new(writeRecorder)
+	l = newTestLogger(io.Discard, "").AddBufferedOutput(rec, BufferedSize(64).Oversize(OversizeTruncate))
+	l.Info("small")
+	l.Info(strings.Repeat("x", 100))
+	l.Flush()
+	out := strings.Join(rec.writes, "")
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "small") || len(lines[1]) != 63 || !strings.HasSuffix(lines[1], truncatedMarker) {
+		t.Fatalf("truncate policy: unexpected output %q", out)
+	}
+}
+
+func TestPostClose(t *testing.T) {
+	sized := new(bytes.Buffer)
+	timed := new(syncBuffer)
+	l := newTestLogger(io.Discard, "").
+		AddBufferedOutput(sized, BufferedSize(4096)).
+		AddBufferedOutput(timed, BufferedInterval(time.Hour))
+	l.Info("before")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("after")
+	for _, out := range []string{sized.String(), timed.String()} {
+		if !strings.HasSuffix(out, "after\n") {
+			t.Fatalf("line after Close not written synchronously: %q", out)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	var errs []error
+	sized.Reset()
+	l = newTestLogger(io.Discard, "").
+		AddBufferedOutput(sized, BufferedSize(4096)).
+		SetPostCloseBehavior(PostCloseDrop).
+		SetOnError(func(err error) { errs = append(errs, err) })
+	l.Info("before")
+	l.Close()
+	l.Info("after")
+	l.Block(InfoLevel, "a", "b")
+	if out := sized.String(); strings.Contains(out, "after") || strings.Contains(out, "] a") {
+		t.Fatalf("line after Close not dropped: %q", out)
+	}
+	if len(errs) != 2 || !errors.Is(errs[0], ErrClosed) || l.Stats().Dropped != 2 {
+		t.Fatalf("errs = %v, dropped = %d", errs, l.Stats().Dropped)
+	}
+
+	l.AddBufferedOutput(io.Discard, BufferedSize(64))
+	l.Info("reopened")
+	if len(errs) != 2 {
+		t.Fatalf("dropped after reopening: %v", errs)
+	}
+}
@@ -0,0 +1,150 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	oldOut, newOut, newJSON := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	oldCfg := Config{Level: InfoLevel, Outputs: []io.Writer{oldOut}, Banner: "old"}
+	newCfg := Config{Level: WarnLevel, Outputs: []io.Writer{newOut}, JSONOutputs: []io.Writer{newJSON}, Banner: "new", Color: true, MaxLineBytes: 256}
+	l := newTestLogger(io.Discard, "").Apply(oldCfg)
+
+	isOld := func(c Config) bool {
+		return c.Level == InfoLevel && slices.Equal(c.Outputs, oldCfg.Outputs) && c.JSONOutputs == nil &&
+			c.Banner == "[old]" && !c.Color && c.MaxLineBytes == 0
+	}
+	isNew := func(c Config) bool {
+		return c.Level == WarnLevel && slices.Equal(c.Outputs, newCfg.Outputs) && slices.Equal(c.JSONOutputs, newCfg.JSONOutputs) &&
+			c.Banner == "[new]" && c.Color && c.MaxLineBytes == 256
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if c := l.Config(); !isOld(c) && !isNew(c) {
+				t.Errorf("observed intermediate config: %+v", c)
+				return
+			}
+		}
+	}()
+	for i := range 1001 { // 最后一次为 newCfg
+		if i%2 == 0 {
+			l.Apply(newCfg)
+		} else {
+			l.Apply(oldCfg)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	l.Info("hidden")
+	l.Warn("shown")
+	if oldOut.Len() != 0 || !strings.Contains(newOut.String(), "[new] shown") || strings.Contains(newOut.String(), "hidden") {
+		t.Fatalf("text outputs: old %q, new %q", oldOut.String(), newOut.String())
+	}
+	if !strings.Contains(newJSON.String(), `"msg":"shown"`) {
+		t.Fatalf("JSON output: %q", newJSON.String())
+	}
+
+	var errs []error
+	l.SetOnError(func(err error) { errs = append(errs, err) }).
+		Apply(Config{Level: DebugLevel, Color: true, FullJSON: true})
+	if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidConfig) || !isNew(l.Config()) {
+		t.Fatalf("invalid config applied: %v, %+v", errs, l.Config())
+	}
+	if err := (&Config{Banner: "a\nb"}).Validate(); !errors.Is(err, ErrInvalidConfig) || !errors.Is(err, ErrInvalidBanner) {
+		t.Fatalf("Validate() = %v", err)
+	}
+}
+
+func TestApplyWhileLogging(t *testing.T) {
+	out := new(bytes.Buffer)
+	textCfg := Config{Level: InfoLevel, Outputs: []io.Writer{out}, Banner: "text", Color: true}
+	jsonCfg := Config{Level: InfoLevel, Outputs: []io.Writer{out}, Banner: "json", FullJSON: true}
+	l := newTestLogger(io.Discard, "").Apply(textCfg)
+
+	stop := make(chan struct{})
+	applied := make(chan struct{})
+	go func() {
+		defer close(applied)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				l.Apply(jsonCfg)
+			} else {
+				l.Apply(textCfg)
+			}
+			runtime.Gosched()
+		}
+	}()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 500 {
+				l.Info("line")
+				l.WithField("k", 1).Info("child")
+				l.Block(InfoLevel, "a", "b")
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	<-applied
+
+	for line := range strings.Lines(out.String()) {
+		if strings.HasPrefix(line, "{") {
+			if !strings.Contains(line, `"banner":"[json]"`) {
+				t.Fatalf("JSON line with stale banner: %q", line)
+			}
+		} else if !strings.HasPrefix(line, LevelBannerC[InfoLevel]) || !strings.Contains(line, "[text]") {
+			t.Fatalf("text line mixes old and new settings: %q", line)
+		}
+	}
+}
+
+func TestConfigWhileSetLevel(t *testing.T) {
+	l := newTestLogger(io.Discard, "")
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			l.SetLevel(Level(i % 2))
+			runtime.Gosched()
+		}
+	}()
+	for range 1000 {
+		if level := l.Config().Level; level != TraceLevel && level != DebugLevel {
+			t.Fatalf("Config().Level = %v", level)
+		}
+		runtime.Gosched()
+	}
+	close(stop)
+	<-done
+}
@@ -0,0 +1,37 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type ctxKey string
+
+func TestContextFields(t *testing.T) {
+	RegisterContextField("tenant", ctxKey("tenant"))
+	RegisterContextField("request_id", ctxKey("rid"))
+	RegisterContextField("user", ctxKey("missing"))
+	t.Cleanup(func() {
+		contextFields.Lock()
+		contextFields.list = nil
+		contextFields.Unlock()
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey("tenant"), "acme")
+	ctx = context.WithValue(ctx, ctxKey("rid"), 42)
+
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "ctx")
+	l.InfoCtx(ctx, "handled")
+	l.WithContext(ctx).Warn("derived")
+	l.Info("plain")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, want := range []string{"handled tenant=acme request_id=42", "derived tenant=acme request_id=42", "plain"} {
+		if !strings.HasSuffix(lines[i], "[ctx] "+want) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+//go:build windows
+
+package SimpleLog
+
+import "testing"
+
+type mockEvent struct {
+	kind string
+	msg  string
+}
+
+type mockEventLog struct {
+	events []mockEvent
+}
+
+func (m *mockEventLog) Info(eid uint32, msg string) error {
+	m.events = append(m.events, mockEvent{"info", msg})
+	return nil
+}
+
+func (m *mockEventLog) Warning(eid uint32, msg string) error {
+	m.events = append(m.events, mockEvent{"warning", msg})
+	return nil
+}
+
+func (m *mockEventLog) Error(eid uint32, msg string) error {
+	m.events = append(m.events, mockEvent{"error", msg})
+	return nil
+}
+
+func (m *mockEventLog) Close() error { return nil }
+
+func TestEventLogWriter(t *testing.T) {
+	mock := new(mockEventLog)
+	l := newTestLogger(&EventLogWriter{log: mock, EventID: 1}, "svc")
+	l.Debug("d")
+	l.Info("i")
+	l.Warn("w")
+	l.Error("e")
+	l.Block(FatalLevel, "f")
+
+	want := []string{"info", "info", "warning", "error", "error"}
+	if len(mock.events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(mock.events), len(want))
+	}
+	for i, e := range mock.events {
+		if e.kind != want[i] {
+			t.Errorf("event %d type = %s, want %s", i, e.kind, want[i])
+		}
+		if e.msg[len(e.msg)-1] == '\n' {
+			t.Errorf("event %d keeps trailing newline: %q", i, e.msg)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrentFatal(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var exits atomic.Int32
+	l := newTestLogger(buf, "").SetExitFunc(func(code int) {
+		if code != 1 {
+			t.Errorf("exit code = %d, want 1", code)
+		}
+		exits.Add(1)
+	})
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				l.Fatal("crash")
+			} else {
+				l.Fatalf("crash %d", i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := exits.Load(); n != 1 {
+		t.Fatalf("exit func ran %d times, want 1", n)
+	}
+	if n := strings.Count(buf.String(), "[FATAL]"); n != 1 {
+		t.Fatalf("got %d fatal lines, want 1: %q", n, buf.String())
+	}
+}
+
+func TestFatalExitCode(t *testing.T) {
+	var code int
+	exit := func(c int) { code = c }
+
+	newTestLogger(new(bytes.Buffer), "").SetExitFunc(exit).SetFatalExitCode(3).Fatal("config")
+	if code != 3 {
+		t.Fatalf("exit code = %d, want 3", code)
+	}
+
+	newTestLogger(new(bytes.Buffer), "").SetExitFunc(exit).SetFatalExitCode(3).
+		SetExitCodeFunc(func(level Level) int { return 10 + int(level) }).Fatalf("runtime %d", 1)
+	if want := 10 + int(FatalLevel); code != want {
+		t.Fatalf("exit code = %d, want %d", code, want)
+	}
+}
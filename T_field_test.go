@@ -0,0 +1,208 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogKV(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "kv")
+	for _, c := range []struct {
+		field Field
+		want  string
+	}{
+		{String("s", "plain"), "s=plain"},
+		{String("s", "with space"), `s="with space"`},
+		{String("s", ""), `s=""`},
+		{Int("i", -42), "i=-42"},
+		{Bool("b", true), "b=true"},
+		{Bool("b", false), "b=false"},
+		{Dur("d", 1500*time.Millisecond), "d=1.5s"},
+	} {
+		buf.Reset()
+		l.LogKV(InfoLevel, c.field)
+		if out := buf.String(); !strings.HasSuffix(out, "[kv] "+c.want+"\n") {
+			t.Errorf("got %q, want suffix %q", out, c.want)
+		}
+	}
+
+	buf.Reset()
+	l.LogKV(InfoLevel, String("a", "1"), Int("b", 2))
+	if out := buf.String(); !strings.HasSuffix(out, "[kv] a=1 b=2\n") {
+		t.Errorf("got %q", out)
+	}
+
+	buf.Reset()
+	l.SetLevel(WarnLevel)
+	l.LogKV(InfoLevel, Int("n", 1))
+	if buf.Len() != 0 {
+		t.Errorf("disabled level emitted %q", buf.String())
+	}
+}
+
+func TestFieldQuoting(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "")
+	fields := []Field{String("plain", "abc"), String("space", "a b"), Any("list", []string{"x", "y,z"}), Int("n", 1)}
+	for _, tc := range []struct {
+		q    FieldQuoting
+		want string
+	}{
+		{QuoteMinimal, ` plain=abc space="a b" list=[x,"y,z"] n=1`},
+		{QuoteAlways, ` plain="abc" space="a b" list=["x","y,z"] n=1`},
+		{QuoteNever, ` plain=abc space=a b list=[x,y,z] n=1`},
+	} {
+		buf.Reset()
+		l.SetFieldQuoting(tc.q).LogKV(InfoLevel, fields...)
+		if got := buf.String(); !strings.HasSuffix(got, tc.want+"\n") {
+			t.Errorf("quoting %d: got %q, want suffix %q", tc.q, got, tc.want)
+		}
+	}
+}
+
+func TestTimeFields(t *testing.T) {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 500, time.UTC)
+	fields := []Field{Any("at", at), Any("wait", 1500*time.Millisecond), Dur("took", time.Second)}
+
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "")
+	l.LogKV(InfoLevel, fields...)
+	if want := " at=2024-05-06T07:08:09.0000005Z wait=1.5s took=1s\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Fatalf("got %q, want suffix %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	l.SetFullJSON(true).LogKV(InfoLevel, fields...)
+	if want := `"fields":{"at":"2024-05-06T07:08:09.0000005Z","wait":"1.5s","took":"1s"}`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestBytesFields(t *testing.T) {
+	b := []byte{0xde, 0xad, 0xbe, 0xef, '?'}
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "")
+	l.LogKV(InfoLevel, Hex("sum", b), Base64("raw", b))
+	if want := " sum=deadbeef3f raw=3q2+7z8=\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Fatalf("got %q, want suffix %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	l.SetFullJSON(true).LogKV(InfoLevel, Hex("sum", b), Base64("raw", b))
+	if want := `"fields":{"sum":"deadbeef3f","raw":"3q2+7z8="}`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func BenchmarkLogKV(b *testing.B) {
+	l := newTestLogger(io.Discard, "bench")
+	b.ReportAllocs()
+	for b.Loop() {
+		l.LogKV(InfoLevel, String("user", "alice"), Int("id", 12345), Bool("ok", true), Dur("took", time.Millisecond))
+	}
+}
+
+func BenchmarkInfoAny(b *testing.B) {
+	l := newTestLogger(io.Discard, "bench")
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Info("user=", "alice", " id=", 12345, " ok=", true, " took=", time.Millisecond)
+	}
+}
+
+func TestSliceFields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	text := newTestLogger(buf, "s")
+	for _, c := range []struct {
+		value any
+		text  string
+		json  string
+	}{
+		{[]string{"a", "b c", "d,e"}, `[a,"b c","d,e"]`, `["a","b c","d,e"]`},
+		{[]int{1, 2, 3}, "[1,2,3]", "[1,2,3]"},
+		{[2]bool{true, false}, "[true,false]", "[true,false]"},
+		{[]byte("hi!"), "aGkh", `"aGkh"`},
+		{[][]int{{1}, {2, 3}}, "[[1],[2,3]]", "[[1],[2,3]]"},
+		{[]time.Duration{time.Second}, "[1s]", "[1000000000]"},
+		{[]string(nil), "[]", "null"},
+	} {
+		buf.Reset()
+		text.LogKV(InfoLevel, Any("v", c.value))
+		if out := buf.String(); !strings.HasSuffix(out, " v="+c.text+"\n") {
+			t.Errorf("text %#v: got %q, want %q", c.value, out, c.text)
+		}
+
+		buf.Reset()
+		text.Clone().SetFullJSON(true).LogKV(InfoLevel, Any("v", c.value))
+		if out := buf.String(); !strings.Contains(out, `"fields":{"v":`+c.json+`}`) {
+			t.Errorf("json %#v: got %q, want %q", c.value, out, c.json)
+		}
+	}
+}
+
+func TestLazyField(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := NewDual(buf, io.Discard).SetLevel(InfoLevel)
+	setColor(l, false)
+	calls := 0
+	heavy := Lazy("payload", func() any {
+		calls++
+		return "big"
+	})
+
+	l.LogKV(DebugLevel, heavy)
+	if calls != 0 {
+		t.Fatalf("lazy field evaluated for a disabled level")
+	}
+
+	l.SetFilter(func(e *Event) bool { return e.Message != "drop" })
+	l.Sugar().Infow("drop", heavy)
+	if calls != 0 {
+		t.Fatalf("lazy field evaluated for a filtered event")
+	}
+
+	l.LogKV(InfoLevel, heavy)
+	if calls != 1 {
+		t.Fatalf("lazy field evaluated %d times, want 1", calls)
+	}
+	if !strings.HasSuffix(buf.String(), " payload=big\n") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestDurationPrecision(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "").SetDurationPrecision(time.Millisecond)
+	l.LogKV(InfoLevel, Dur("took", 12384700*time.Nanosecond), Any("wait", 1500*time.Microsecond))
+	if !strings.HasSuffix(buf.String(), " took=12ms wait=2ms\n") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+
+	buf.Reset()
+	l.SetDurationPrecision(0).LogKV(InfoLevel, Dur("took", 12384700*time.Nanosecond))
+	if !strings.HasSuffix(buf.String(), " took=12.3847ms\n") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestFieldScope(t *testing.T) {
+	text, structured := new(bytes.Buffer), new(bytes.Buffer)
+	l := newTestLogger(text, "").AddJSONOutput(structured)
+	l.LogKV(InfoLevel,
+		Any("payload", []int{1, 2}).Scope(ScopeJSONOnly),
+		String("id", "a"),
+		String("hint", "see console").Scope(ScopeTextOnly),
+		Lazy("body", func() any { return "raw" }).Scope(ScopeJSONOnly),
+	)
+
+	if out := text.String(); !strings.HasSuffix(out, "] id=a hint=\"see console\"\n") {
+		t.Fatalf("text output: %q", out)
+	}
+	if out := structured.String(); !strings.Contains(out, `"fields":{"payload":[1,2],"id":"a","body":"raw"}`) {
+		t.Fatalf("JSON output: %q", out)
+	}
+}
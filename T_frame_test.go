@@ -0,0 +1,81 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	pipe := new(bytes.Buffer)
+	l := newTestLogger(io.Discard, "ipc").AddJSONOutput(NewFrameWriter(pipe)).WithField("node", "a")
+	l.LogKV(WarnLevel, String("q", "line\nbreak"), Int("rows", 3), Bool("ok", false), Any("ratio", 0.5))
+	l.Block(InfoLevel, "one", "two")
+
+	e, err := ReadFrame(pipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Level != WarnLevel || e.Banner != "[ipc]" || e.Message != "" || e.Time.IsZero() || e.Caller.Line == 0 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	want := []Field{String("node", "a"), String("q", "line\nbreak"), Int("rows", 3), Bool("ok", false), Any("ratio", 0.5)}
+	if len(e.Fields) != len(want) {
+		t.Fatalf("got fields %+v", e.Fields)
+	}
+	for i := range want {
+		if e.Fields[i] != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, e.Fields[i], want[i])
+		}
+	}
+
+	for _, msg := range []string{"one", "two"} {
+		e, err := ReadFrame(pipe)
+		if err != nil || e.Message != msg {
+			t.Fatalf("ReadFrame() = %+v, %v, want message %q", e, err, msg)
+		}
+	}
+	if _, err := ReadFrame(pipe); !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadFrame at end = %v, want io.EOF", err)
+	}
+
+	if _, err := ReadFrame(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff})); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("oversized frame: %v", err)
+	}
+}
+
+// frame 为 payload 加上长度前缀
+func frame(payload string) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(payload))), payload...)
+}
+
+func TestReadFrameMalformed(t *testing.T) {
+	for _, payload := range []string{
+		`{"fields":[1,2]}`,
+		`{"fields":"x"}`,
+		`{"fields":{"a":}}`,
+		`not json`,
+	} {
+		if e, err := ReadFrame(bytes.NewReader(frame(payload))); err == nil {
+			t.Errorf("ReadFrame(%s) = %+v, want error", payload, e)
+		}
+	}
+	if _, err := ReadFrame(bytes.NewReader(frame(`{"fields":[1]}`))); !errors.Is(err, ErrInvalidFrame) {
+		t.Errorf("non-object fields: %v", err)
+	}
+	if e, err := ReadFrame(bytes.NewReader(frame(`{"fields":null,"msg":"m"}`))); err != nil || e.Message != "m" || e.Fields != nil {
+		t.Errorf("null fields: %+v, %v", e, err)
+	}
+
+	if _, err := ReadFrame(bytes.NewReader(frame("{}")[:4])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated frame: %v", err)
+	}
+	broken := errors.New("connection reset")
+	r := io.MultiReader(bytes.NewReader(frame("{}")[:5]), iotest.ErrReader(broken))
+	if _, err := ReadFrame(r); !errors.Is(err, broken) {
+		t.Errorf("read error replaced: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package SimpleLog
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	buf := new(syncBuffer)
+	l := newTestLogger(buf, "hb").SetHeartbeatQuietPeriod(time.Minute)
+	ticks := make(chan time.Time)
+	l.tick = func(time.Duration) (<-chan time.Time, func()) { return ticks, func() {} }
+	var now atomic.Int64 // 跳过的心跳同样会读取时钟, 与测试并发
+	now.Store(processStart.Add(time.Hour).UnixNano())
+	l.now = func() time.Time { return time.Unix(0, now.Load()) }
+	waitCount := func(n int) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for strings.Count(buf.String(), "alive uptime=") != n {
+			if time.Now().After(deadline) {
+				t.Fatalf("want %d heartbeats, got %q", n, buf.String())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	stop := l.StartHeartbeat(time.Minute, InfoLevel, "alive")
+	ticks <- time.Time{}
+	waitCount(1)
+	ticks <- time.Time{}
+	waitCount(2)
+	if out := buf.String(); !strings.Contains(out, "alive uptime=1h0m0s goroutines=") || !strings.Contains(out, " heap_alloc=") {
+		t.Fatalf("missing uptime or runtime stats: %q", out)
+	}
+
+	// 最近有其他日志时跳过, 第二次发送返回时第一次已处理完毕
+	buf.Reset()
+	l.Info("busy")
+	ticks <- time.Time{}
+	ticks <- time.Time{}
+	if strings.Contains(buf.String(), "alive") {
+		t.Fatalf("heartbeat not skipped while active: %q", buf.String())
+	}
+	now.Add(int64(2 * time.Minute))
+	ticks <- time.Time{}
+	waitCount(1)
+
+	// 子实例的输出, 以及 LogAt 回放的旧事件同样视为最近的活动
+	buf.Reset()
+	l.WithField("k", 1).Info("child")
+	ticks <- time.Time{}
+	ticks <- time.Time{}
+	now.Add(int64(2 * time.Minute))
+	l.LogAt(processStart, InfoLevel, "replayed")
+	ticks <- time.Time{}
+	ticks <- time.Time{}
+	if strings.Contains(buf.String(), "alive") {
+		t.Fatalf("heartbeat not skipped after child or replayed output: %q", buf.String())
+	}
+
+	stop()
+	stop()
+	select {
+	case ticks <- time.Time{}:
+		t.Fatal("heartbeat still running after stop")
+	default:
+	}
+}
@@ -0,0 +1,105 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFullJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "svc").
+		WithField("user", "alice").
+		SetFullJSON(true)
+	l.Warn("slow \"request\"\n")
+	l.LogKV(InfoLevel, Int("n", 3), Dur("took", time.Second), Bool("ok", true))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines, got %q", buf.String())
+	}
+
+	var e map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[0], err)
+	}
+	for _, key := range []string{"level", "level_num", "time", "banner", "caller", "fields", "msg"} {
+		if _, ok := e[key]; !ok {
+			t.Errorf("missing key %q in %s", key, lines[0])
+		}
+	}
+	if e["level"] != "WARN" || e["level_num"] != float64(WarnLevel) || e["banner"] != "[svc]" || e["msg"] != "slow \"request\"\n" {
+		t.Errorf("unexpected values: %v", e)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, e["time"].(string)); err != nil {
+		t.Errorf("time not RFC3339Nano: %v", err)
+	}
+	c := e["caller"].(map[string]any)
+	if !strings.HasSuffix(c["file"].(string), "T_json_test.go") || c["line"].(float64) == 0 || !strings.HasSuffix(c["func"].(string), "TestFullJSON") {
+		t.Errorf("unexpected caller: %v", c)
+	}
+	if f := e["fields"].(map[string]any); f["user"] != "alice" {
+		t.Errorf("unexpected fields: %v", f)
+	}
+
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[1], err)
+	}
+	f := e["fields"].(map[string]any)
+	if f["user"] != "alice" || f["n"] != float64(3) || f["took"] != "1s" || f["ok"] != true {
+		t.Errorf("unexpected fields: %v", f)
+	}
+}
+
+func TestNewDual(t *testing.T) {
+	console, structured := new(bytes.Buffer), new(bytes.Buffer)
+	l := NewDual(console, structured).WithField("req", 7)
+	l.Info("hello")
+
+	if out := console.String(); !strings.HasPrefix(out, LevelBannerC[InfoLevel]) || !strings.HasSuffix(out, " hello req=7\n") {
+		t.Errorf("unexpected console output: %q", out)
+	}
+	var e map[string]any
+	if err := json.Unmarshal(structured.Bytes(), &e); err != nil {
+		t.Fatalf("structured output is not JSON: %q: %v", structured.String(), err)
+	}
+	if e["msg"] != "hello" || e["level"] != "INFO" || e["fields"].(map[string]any)["req"] != float64(7) {
+		t.Errorf("unexpected structured output: %v", e)
+	}
+}
+
+func TestLogPanicJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "sup").SetFullJSON(true)
+	l.LogPanic("boom")
+
+	var e map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if e["msg"] != "panic: boom" || !strings.HasPrefix(e["stack"].(string), "goroutine ") {
+		t.Errorf("unexpected event: %v", e)
+	}
+}
+
+func TestOmitEmptyMessage(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "").SetFullJSON(true)
+	l.LogKV(InfoLevel, Int("n", 1))
+	if !strings.Contains(buf.String(), `"msg":""`) {
+		t.Fatalf("empty msg omitted by default: %q", buf.String())
+	}
+
+	buf.Reset()
+	l.SetOmitEmptyMessage(true).LogKV(InfoLevel, Int("n", 1))
+	l.Info("kept")
+	lines := strings.Split(buf.String(), "\n")
+	if strings.Contains(lines[0], `"msg"`) || !strings.HasSuffix(lines[0], `"fields":{"n":1}}`) {
+		t.Fatalf("msg key not omitted: %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], `"msg":"kept"}`) {
+		t.Fatalf("non-empty msg omitted: %q", lines[1])
+	}
+}
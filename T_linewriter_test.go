@@ -0,0 +1,70 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "")
+	w := l.Writer(WarnLevel)
+	fmt.Fprint(w, "first\r\nsec")
+	fmt.Fprint(w, "ond\nthi")
+	w.Close()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{"first", "second", "thi"}
+	if len(lines) != len(want) {
+		t.Fatalf("got %q", buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, " [WARN]") || !strings.HasSuffix(line, " "+want[i]) {
+			t.Errorf("line %d = %q, want suffix %q", i, line, want[i])
+		}
+	}
+}
+
+func TestTaggedWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "build")
+	w1 := l.TaggedWriter(InfoLevel, "[worker-1]")
+	w2 := l.TaggedWriter(InfoLevel, "[worker-2]")
+	fmt.Fprint(w1, "compiling a")
+	fmt.Fprint(w2, "compiling b\n")
+	fmt.Fprint(w1, ".go\n")
+	fmt.Fprint(w2, "done\n")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{"[worker-2] compiling b", "[worker-1] compiling a.go", "[worker-2] done"}
+	if len(lines) != len(want) {
+		t.Fatalf("got %q", buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, "[build] "+want[i]) {
+			t.Errorf("line %d = %q, want suffix %q", i, line, want[i])
+		}
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "legacy").SetLevel(InfoLevel)
+	std := l.StdLogger(WarnLevel)
+	std.Printf("disk at %d%%", 91)
+	std.Print("no newline")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], " [WARN]") || !strings.HasSuffix(lines[0], "[legacy] disk at 91%") ||
+		!strings.HasSuffix(lines[1], "[legacy] no newline") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+
+	buf.Reset()
+	l.StdLogger(DebugLevel).Print("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("disabled level produced output: %q", buf.String())
+	}
+}
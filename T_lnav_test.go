@@ -0,0 +1,42 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLnavCompatible(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "svc").SetLnavCompatible(true).SetLevel(TraceLevel)
+	setColor(l, true)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 6e6, time.FixedZone("", 8*3600))
+	l.now = func() time.Time { return now }
+
+	l.Warn("slow")
+	l.LogKV(InfoLevel, Int("n", 1))
+	l.Print(PanicLevel, "boom")
+	l.SetBanner("").Debug("no banner")
+
+	pattern := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}(Z|[+-]\d{2}:\d{2}) (TRACE|DEBUG|INFO|WARNING|ERROR|FATAL|CRITICAL)( \[[^]]*\])?( .*)?$`)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		"2026-01-02T03:04:05.006+08:00 WARNING [svc] slow",
+		"2026-01-02T03:04:05.006+08:00 INFO [svc] n=1",
+		"2026-01-02T03:04:05.006+08:00 CRITICAL [svc] boom",
+		"2026-01-02T03:04:05.006+08:00 DEBUG no banner",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %q", lines)
+	}
+	for i, line := range lines {
+		if line != want[i] {
+			t.Errorf("line %d = %q, want %q", i, line, want[i])
+		}
+		if !pattern.MatchString(line) {
+			t.Errorf("line %q does not match the documented layout", line)
+		}
+	}
+}
@@ -1,6 +1,27 @@
 package SimpleLog
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestLogger 创建一个不与全局实例共享配置的日志实例
+func newTestLogger(w io.Writer, banner string) *Logger {
+	l := &Logger{logger: newLogger(w)}
+	return l.SetBanner(banner)
+}
+
+func setColor(l *Logger, color bool) {
+	l.update(func(s *settings) { s.color = color })
+}
 
 func TestLog(t *testing.T) {
 	logger := New("Test", true, true)
@@ -10,3 +31,550 @@ func TestLog(t *testing.T) {
 		logger.Print(i, "Test \n message")
 	}
 }
+
+func TestEnabler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	a := newTestLogger(buf, "a")
+	b := &Logger{logger: a.logger, view: newView(&settings{banner: "[b]"})}
+	a.SetLevel(ErrorLevel)
+	a.SetEnabler(func(level Level, banner string) bool {
+		return banner == "[a]"
+	})
+
+	a.Debug("from a")
+	b.Error("from b")
+	if out := buf.String(); !strings.Contains(out, "from a") || strings.Contains(out, "from b") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	buf.Reset()
+	a.SetEnabler(nil)
+	a.Debug("from a")
+	b.Error("from b")
+	if out := buf.String(); strings.Contains(out, "from a") || !strings.Contains(out, "from b") {
+		t.Fatalf("unexpected output after reset: %q", out)
+	}
+}
+
+func TestClone(t *testing.T) {
+	parentBuf, childBuf := new(bytes.Buffer), new(bytes.Buffer)
+	parent := newTestLogger(parentBuf, "parent")
+	child := parent.Clone().SetBanner("child")
+
+	child.SetLevel(ErrorLevel)
+	if parent.Level != TraceLevel {
+		t.Fatalf("child SetLevel changed parent level to %d", parent.Level)
+	}
+	parent.SetLevel(WarnLevel)
+	if child.Level != ErrorLevel {
+		t.Fatalf("parent SetLevel changed child level to %d", child.Level)
+	}
+
+	// 输出在写时复制前共享
+	child.Error("shared")
+	if !strings.Contains(parentBuf.String(), "[child] shared") {
+		t.Fatalf("child did not write to shared output: %q", parentBuf.String())
+	}
+
+	parentBuf.Reset()
+	child.AddOutput(childBuf)
+	parent.Error("parent only")
+	child.Error("both")
+	if out := childBuf.String(); strings.Contains(out, "parent only") || !strings.Contains(out, "both") {
+		t.Fatalf("unexpected child output: %q", out)
+	}
+	if out := parentBuf.String(); !strings.Contains(out, "parent only") || !strings.Contains(out, "both") {
+		t.Fatalf("unexpected parent output: %q", out)
+	}
+}
+
+func TestWithField(t *testing.T) {
+	buf := new(bytes.Buffer)
+	parent := newTestLogger(buf, "f").WithField("a", 1)
+	child := parent.WithField("b", "x y")
+	sibling := parent.WithField("c", true)
+
+	child.Info("msg")
+	sibling.Info("msg")
+	parent.Info("msg")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, want := range []string{`msg a=1 b="x y"`, "msg a=1 c=true", "msg a=1"} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	buf, buffered := new(bytes.Buffer), new(bytes.Buffer)
+	l := newTestLogger(buf, "r").
+		SetLevel(ErrorLevel).
+		SetEscapeNewline(true).
+		SetFullJSON(true).
+		SetMaxLineBytes(4096).
+		SetSyncLevel(WarnLevel).
+		SetFatalExitCode(3).
+		AddBufferedOutput(buffered, BufferedSize(4096)).
+		WithField("k", "v")
+	setColor(l, true)
+	l.Error("pending")
+
+	if l.Reset() != l {
+		t.Fatal("Reset did not return the logger")
+	}
+	if !strings.Contains(buffered.String(), "pending") {
+		t.Fatalf("buffered output not flushed before Reset: %q", buffered.String())
+	}
+	if s := *l.settings(); s != (settings{banner: "[r]"}) || len(l.fields) != 0 {
+		t.Fatalf("instance settings not reset: %+v %+v", s, *l)
+	}
+	if l.Level != TraceLevel || l.Out != os.Stderr || l.jsonOut != nil || len(l.buffers) != 0 ||
+		l.maxLineBytes != 0 || l.syncLevel != levelOff || l.exitCode != 1 || l.counters.lines.Load() != 0 {
+		t.Fatalf("shared settings not reset: %+v", *l.logger)
+	}
+	l.SetOutput(buf).Info("after reset")
+	if out := buf.String(); !strings.HasSuffix(out, "[r] after reset\n") {
+		t.Fatalf("unexpected output after Reset: %q", out)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	buf := new(bytes.Buffer)
+	req := newTestLogger(buf, "req").WithField("id", 7).WithField("user", "alice")
+	comp := newTestLogger(io.Discard, "db").WithField("component", "pool").WithField("id", 1)
+
+	merged := req.Merge(comp)
+	merged.Info("query")
+	req.Info("done")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "[req] query id=7 user=alice component=pool") || !strings.HasSuffix(lines[1], "[req] done id=7 user=alice") {
+		t.Fatalf("unexpected output: %q", lines)
+	}
+	if len(comp.fields) != 2 {
+		t.Fatalf("other logger modified: %+v", comp.fields)
+	}
+}
+
+func TestFieldProvider(t *testing.T) {
+	buf := new(bytes.Buffer)
+	role := "follower"
+	calls := 0
+	l := newTestLogger(buf, "").SetLevel(InfoLevel).WithField("svc", "db").
+		AddFieldProvider(func() (string, any) {
+			calls++
+			return "role", role
+		})
+
+	l.Info("a")
+	role = "leader"
+	l.LogKV(InfoLevel, Int("term", 2))
+	l.Debug("hidden")
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasSuffix(lines[0], " a svc=db role=follower") || !strings.HasSuffix(lines[1], " svc=db role=leader term=2") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+	if calls != 2 {
+		t.Fatalf("provider called %d times, want 2", calls)
+	}
+}
+
+func TestShowGap(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "gap").SetShowGap(time.Second)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+	l.now = func() time.Time { return now }
+
+	l.Info("first")
+	now = now.Add(3200 * time.Millisecond)
+	l.Info("second")
+	now = now.Add(100 * time.Millisecond)
+	l.Info("third")
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasSuffix(lines[1], "[gap] +3.2s second") {
+		t.Errorf("missing gap marker: %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], "[gap] third") {
+		t.Errorf("unexpected gap marker: %q", lines[2])
+	}
+}
+
+func TestSubsecondDigits(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var errs []error
+	l := newTestLogger(buf, "").SetOnError(func(err error) { errs = append(errs, err) })
+	now := time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.Local)
+	l.now = func() time.Time { return now }
+	l.Info("prime") // 首行可能带有日期
+
+	for _, tc := range []struct {
+		n    int
+		want string
+	}{{0, "[03:04:05]"}, {3, "[03:04:05.123]"}, {6, "[03:04:05.123456]"}} {
+		buf.Reset()
+		l.SetSubsecondDigits(tc.n).Info("x")
+		if !strings.HasPrefix(buf.String(), " [INFO]"+tc.want+" x") {
+			t.Errorf("digits %d: got %q, want %q", tc.n, buf.String(), tc.want)
+		}
+	}
+
+	l.SetSubsecondDigits(10).SetSubsecondDigits(-1)
+	buf.Reset()
+	l.Info("x")
+	if len(errs) != 2 || !strings.Contains(buf.String(), "[03:04:05.123456]") {
+		t.Fatalf("invalid digits not rejected: errs=%v, out=%q", errs, buf.String())
+	}
+}
+
+func TestLevelTimeFormat(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "").
+		SetLevelTimeFormat(DebugLevel, "[05.000]").
+		SetLevelTimeFormat(InfoLevel, "[2006-01-02 15:04:05]")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.Local)
+	l.now = func() time.Time { return now }
+	l.Warn("prime")
+
+	buf.Reset()
+	l.Debug("d")
+	l.Info("i")
+	l.Warn("w")
+	lines := strings.Split(buf.String(), "\n")
+	for i, want := range []string{"[DEBUG][05.123] d", " [INFO][2026-01-02 03:04:05] i", " [WARN][03:04:05.123] w"} {
+		if lines[i] != want {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want)
+		}
+	}
+
+	buf.Reset()
+	l.SetLevelTimeFormat(DebugLevel, "").Debug("d")
+	if want := "[DEBUG][03:04:05.123] d\n"; buf.String() != want {
+		t.Errorf("reset: got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTimestampBannerColor(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "c").SetTimestampColor("90").SetBannerColor("1;36")
+
+	l.Info("plain")
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Fatalf("colored without color enabled: %q", buf.String())
+	}
+
+	buf.Reset()
+	setColor(l, true)
+	l.Info("msg")
+	out := buf.String()
+	if !strings.Contains(out, "\x1b[m\x1b[90m[") || !strings.Contains(out, "]\x1b[m\x1b[1;36m[c]\x1b[m msg") {
+		t.Fatalf("codes do not wrap timestamp and banner: %q", out)
+	}
+}
+
+func TestColorFunc(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "c").SetColorFunc(func(level Level, msg string) (string, string) {
+		if strings.HasPrefix(msg, "again") {
+			return "\x1b[1;91m", "\x1b[m"
+		}
+		return "\x1b[91m", "\x1b[m"
+	})
+	setColor(l, true)
+	l.Error("first")
+	l.Error("again")
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasPrefix(lines[0], "\x1b[91m[ERROR]\x1b[m") || !strings.HasPrefix(lines[1], "\x1b[1;91m[ERROR]\x1b[m") {
+		t.Fatalf("color func not applied: %q", buf.String())
+	}
+
+	buf.Reset()
+	setColor(l, false)
+	l.Error("again")
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Fatalf("colored without color enabled: %q", buf.String())
+	}
+}
+
+func TestSafeLevelColors(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "c").SetLevelColors(SafeLevelColors).SetANSIReset("\x1b[0m").
+		SetGutter(true).SetTimestampColor("90")
+	setColor(l, true)
+	l.Error("e")
+	l.Block(FatalLevel, "f")
+	l.FakePanic("p")
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1b[91m▌\x1b[0m\x1b[91m[ERROR]\x1b[0m\x1b[90m[") {
+		t.Fatalf("unexpected prefix: %q", out)
+	}
+	if strings.Contains(out, "\x1b[m") {
+		t.Errorf("default reset used: %q", out)
+	}
+	for _, m := range regexp.MustCompile(`\x1b\[([0-9;]*)m`).FindAllStringSubmatch(out, -1) {
+		for _, p := range strings.Split(m[1], ";") {
+			if n, _ := strconv.Atoi(p); n == 5 || n == 6 || n == 7 || n >= 40 && n <= 49 || n >= 100 && n <= 107 {
+				t.Errorf("blink or background code %q in %q", m[0], out)
+			}
+		}
+	}
+}
+
+func TestSetBannerChecked(t *testing.T) {
+	var errs []error
+	l := newTestLogger(io.Discard, "ok").SetOnError(func(err error) { errs = append(errs, err) })
+	for _, banner := range []string{"evil\n[ERROR] forged", "\x1b[2Jclear", "bell\a", "bidi\u202e", "bad\xff"} {
+		if err := l.SetBannerChecked(banner); !errors.Is(err, ErrInvalidBanner) {
+			t.Errorf("SetBannerChecked(%q) = %v", banner, err)
+		}
+		l.SetBanner(banner)
+		if l.settings().banner != "[ok]" {
+			t.Fatalf("banner changed to %q", l.settings().banner)
+		}
+	}
+	if len(errs) != 5 {
+		t.Errorf("OnError called %d times, want 5", len(errs))
+	}
+	if err := l.SetBannerChecked("模块 1"); err != nil || l.settings().banner != "[模块 1]" {
+		t.Errorf("valid banner rejected: %v, %q", err, l.settings().banner)
+	}
+}
+
+func TestCaptureDuring(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "cap")
+
+	captured := l.CaptureDuring(func() {
+		l.Info("inside")
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Warn("from goroutine")
+		}()
+		wg.Wait()
+	})
+	if out := string(captured); !strings.Contains(out, "inside") || !strings.Contains(out, "from goroutine") {
+		t.Fatalf("unexpected captured output: %q", out)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("captured lines leaked to original output: %q", buf.String())
+	}
+
+	l.Info("after")
+	if !strings.Contains(buf.String(), "after") {
+		t.Fatalf("original output not restored: %q", buf.String())
+	}
+}
+
+func TestWithOutputDuring(t *testing.T) {
+	main := new(bytes.Buffer)
+	tee := new(bytes.Buffer)
+	l := newTestLogger(main, "tee")
+
+	l.Info("before")
+	l.WithOutputDuring(tee, func() { l.Info("during") })
+	func() {
+		defer func() { recover() }()
+		l.WithOutputDuring(tee, func() {
+			l.Info("panicking")
+			panic("boom")
+		})
+	}()
+	l.Info("after")
+
+	if got := tee.String(); strings.Contains(got, "before") || !strings.Contains(got, "during") ||
+		!strings.Contains(got, "panicking") || strings.Contains(got, "after") {
+		t.Fatalf("unexpected tee output: %q", got)
+	}
+	if strings.Count(main.String(), "\n") != 4 {
+		t.Fatalf("main output lost lines: %q", main.String())
+	}
+	if l.Out != main {
+		t.Fatalf("output not restored: %#v", l.Out)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestWithOutputDuringUncomparable(t *testing.T) {
+	main := new(bytes.Buffer)
+	l := newTestLogger(main, "fn")
+	var got []string
+	fw := writerFunc(func(p []byte) (int, error) {
+		got = append(got, string(p))
+		return len(p), nil
+	})
+
+	l.WithOutputDuring(fw, func() { l.Info("during") })
+	l.Info("after")
+	l.RemoveOutput(fw) // 无法比较, 不会 panic
+	if len(got) != 1 || !strings.Contains(got[0], "during") {
+		t.Fatalf("func writer got %q", got)
+	}
+	if l.Out != main {
+		t.Fatalf("output not restored: %#v", l.Out)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "flt").WithField("user", "alice")
+	l.SetFilter(func(e *Event) bool {
+		if strings.Contains(e.Message, "secret") {
+			return false
+		}
+		e.Message = strings.ToUpper(e.Message)
+		e.Fields[0] = String("user", "redacted")
+		e.Fields = append(e.Fields, String("env", "prod"))
+		return true
+	})
+	l.SetLevel(InfoLevel)
+
+	l.Debug("below gate")
+	l.Info("the secret")
+	l.Info("hello")
+	if out := buf.String(); !strings.HasSuffix(out, "[flt] HELLO user=redacted env=prod\n") || strings.Count(out, "\n") != 1 {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if l.fields[0].val != "alice" {
+		t.Fatalf("filter modified logger fields: %v", l.fields)
+	}
+}
+
+func TestLogAt(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "replay")
+	at := time.Date(2020, 2, 29, 13, 14, 15, 678_000_000, time.Local)
+
+	l.LogAt(at, InfoLevel, "first")
+	l.LogAtf(at.Add(time.Second), InfoLevel, "n=%d", 2)
+	l.Clone().SetFullJSON(true).LogAt(at, WarnLevel, "json")
+
+	lines := strings.Split(buf.String(), "\n")
+	for i, want := range []string{"[13:14-|02/29][replay] first", "[13:14:16.678][replay] n=2"} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], want)
+		}
+	}
+	if want := `"time":"` + at.Format(time.RFC3339Nano) + `"`; !strings.Contains(lines[2], want) {
+		t.Errorf("JSON line %q missing %s", lines[2], want)
+	}
+}
+
+func TestEmptyMessage(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "e")
+
+	l.Info()
+	l.Info("")
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if !strings.HasSuffix(line, "[e]") {
+			t.Errorf("dangling separator in %q", line)
+		}
+	}
+
+	buf.Reset()
+	l.SetSkipEmpty(true)
+	l.Info()
+	l.Info("")
+	if buf.Len() != 0 {
+		t.Fatalf("empty messages not skipped: %q", buf.String())
+	}
+	l.WithField("k", 1).Info()
+	if !strings.HasSuffix(buf.String(), "[e] k=1\n") {
+		t.Fatalf("message with fields skipped or malformed: %q", buf.String())
+	}
+}
+
+func TestBlock(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "block")
+	noise := &Logger{logger: l.logger, view: newView(&settings{banner: "[noise]"})}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				noise.Info("noise")
+			}
+		}()
+	}
+	for range 50 {
+		l.Block(InfoLevel, "row 1", "row 2", "row 3")
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	blocks := 0
+	for i, line := range lines {
+		if !strings.HasSuffix(line, "[block] row 1") {
+			continue
+		}
+		blocks++
+		if i+2 >= len(lines) || !strings.HasSuffix(lines[i+1], "[block] row 2") || !strings.HasSuffix(lines[i+2], "[block] row 3") {
+			t.Fatalf("block split at line %d: %q", i, lines[i:min(i+3, len(lines))])
+		}
+	}
+	if blocks != 50 {
+		t.Fatalf("found %d blocks, want 50", blocks)
+	}
+}
+
+func TestGutter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "g").SetGutter(true)
+	l.Error("no color")
+	if strings.Contains(buf.String(), "▌") {
+		t.Fatalf("gutter shown without color: %q", buf.String())
+	}
+
+	buf.Reset()
+	setColor(l, true)
+	l.Error("boom")
+	l.Info("ok")
+	lines := strings.Split(buf.String(), "\n")
+	if want := "\x1b[91m▌\x1b[m" + LevelBannerC[ErrorLevel]; !strings.HasPrefix(lines[0], want) {
+		t.Errorf("error line %q does not start with %q", lines[0], want)
+	}
+	if want := "\x1b[97m▌\x1b[m" + LevelBannerC[InfoLevel]; !strings.HasPrefix(lines[1], want) {
+		t.Errorf("info line %q does not start with %q", lines[1], want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	l := newTestLogger(io.Discard, "doc")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+	l.now = func() time.Time { return now }
+	setColor(l, true)
+	l.Warn("") // 首行带有日期, 之后同一天的行仅有时间
+
+	if got, want := l.Render(WarnLevel, "x", true), strings.TrimSuffix(l.Format(WarnLevel, "x"), "\n"); got != want {
+		t.Fatalf("Render() = %q, want %q", got, want)
+	}
+	plain := l.Render(WarnLevel, "x", false)
+	if strings.Contains(plain, "\x1b[") || strings.HasSuffix(plain, "\n") || !strings.HasPrefix(plain, LevelBannerN[WarnLevel]) {
+		t.Fatalf("Render() without color = %q", plain)
+	}
+	if !l.settings().color {
+		t.Fatal("Render changed the logger's color setting")
+	}
+
+	// Render 不消耗下一行输出的日期与间隔标记
+	buf := new(bytes.Buffer)
+	l = newTestLogger(buf, "doc").SetShowGap(time.Second)
+	l.now = func() time.Time { return now }
+	l.Info("first")
+	now = time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	l.Render(InfoLevel, "preview", false)
+	buf.Reset()
+	l.Info("second")
+	if !strings.Contains(buf.String(), "[10:00-|03/01]") || !strings.Contains(buf.String(), " +") {
+		t.Fatalf("Render consumed the date header or gap marker: %q", buf.String())
+	}
+}
@@ -0,0 +1,92 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCounter(t *testing.T) {
+	buf := new(syncBuffer)
+	l := newTestLogger(buf, "stat")
+	ticks := make(chan time.Time)
+	l.tick = func(time.Duration) (<-chan time.Time, func()) { return ticks, func() {} }
+	l.SetMetricInterval(time.Second)
+
+	items, errs := l.Counter("items"), l.Counter("errors")
+	if l.Counter("items") != items {
+		t.Fatal("same name returned a different counter")
+	}
+	// 等待汇总行写入, 第二次发送返回时第一次的汇总已经完成
+	tick := func() {
+		ticks <- time.Time{}
+		ticks <- time.Time{}
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				items.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	errs.Add(2)
+	tick()
+	items.Add(5)
+	tick()
+	tick() // 没有变化, 不输出
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "[stat] metrics items=1000 errors=2") || !strings.HasSuffix(lines[1], "[stat] metrics items=5") {
+		t.Fatalf("unexpected lines: %q", lines)
+	}
+	if items.Value() != 1005 {
+		t.Fatalf("items.Value() = %d", items.Value())
+	}
+
+	items.Inc()
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "metrics items=1\n") {
+		t.Fatalf("Close did not flush counters: %q", buf.String())
+	}
+
+	// 未设置间隔时只累计
+	out := new(bytes.Buffer)
+	l = newTestLogger(out, "")
+	l.Counter("n").Inc()
+	if out.Len() != 0 || l.Counter("n").Value() != 1 {
+		t.Fatalf("counter without interval: %q", out.String())
+	}
+}
+
+func TestCounterSharedByClones(t *testing.T) {
+	buf := new(syncBuffer)
+	l := newTestLogger(buf, "stat").SetMetricInterval(time.Hour)
+	l.WithField("req", 1).Counter("items").Add(5)
+	if l.Counter("items").Value() != 5 {
+		t.Fatalf("child counter detached from parent: %d", l.Counter("items").Value())
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "[stat] metrics items=5\n") {
+		t.Fatalf("Close did not flush child counter: %q", buf.String())
+	}
+
+	// Shutdown 同样输出只有计数器, 没有缓冲输出的实例的剩余增量
+	buf.Reset()
+	l = newTestLogger(buf, "stat").SetMetricInterval(time.Hour)
+	l.Counter("n").Inc()
+	Shutdown()
+	if !strings.HasSuffix(buf.String(), "metrics n=1\n") {
+		t.Fatalf("Shutdown did not flush counters: %q", buf.String())
+	}
+}
@@ -0,0 +1,53 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestModuleLevel(t *testing.T) {
+	buf := new(bytes.Buffer)
+	before := newTestLogger(buf, "db").SetLevel(InfoLevel)
+	other := before.Clone().SetBanner("http")
+
+	SetModuleLevel("db", DebugLevel)
+	defer ClearModuleLevel("db")
+	after := newTestLogger(buf, "[db]").SetLevel(InfoLevel)
+
+	before.Debug("before")
+	after.Debug("after")
+	other.Debug("other")
+	got := buf.String()
+	if !strings.Contains(got, "[db] before") || !strings.Contains(got, "[db] after") || strings.Contains(got, "other") {
+		t.Fatalf("unexpected output: %q", got)
+	}
+
+	ClearModuleLevel("[db]")
+	buf.Reset()
+	before.Debug("cleared")
+	if buf.Len() != 0 {
+		t.Fatalf("override not cleared: %q", buf.String())
+	}
+}
+
+func TestModuleLevelRace(t *testing.T) {
+	l := newTestLogger(new(bytes.Buffer), "race")
+	defer ClearModuleLevel("race")
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			SetModuleLevel("race", Level(i%3))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			l.Info("x")
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,77 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNilArguments(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "")
+
+	var err error
+	l.Error(err)
+	l.WithError(err).Info("no error field")
+	if got := buf.String(); strings.Contains(got, "[ERROR]") || strings.Contains(got, "error=") {
+		t.Fatalf("nil error produced output: %q", got)
+	}
+
+	buf.Reset()
+	l.WithError(errors.New("boom")).Info("with")
+	if !strings.HasSuffix(buf.String(), " with error=boom\n") {
+		t.Fatalf("error field missing: %q", buf.String())
+	}
+
+	var p *int
+	var iface any
+	buf.Reset()
+	l.Info("p=", p, " i=", iface)
+	if !strings.HasSuffix(buf.String(), " p=<nil> i=<nil>\n") {
+		t.Fatalf("default nil text: %q", buf.String())
+	}
+
+	buf.Reset()
+	l.SetNilText("null")
+	l.Info("p=", p, " i=", iface)
+	l.LogKV(InfoLevel, Any("ptr", p), Any("iface", iface), Any("n", 0))
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasSuffix(lines[0], " p=null i=null") || !strings.HasSuffix(lines[1], " ptr=null iface=null n=0") {
+		t.Fatalf("custom nil text: %q", buf.String())
+	}
+
+	// 替换后与默认一样在非字符串参数之间添加空格
+	buf.Reset()
+	l.Info(1, nil, 2)
+	if !strings.HasSuffix(buf.String(), " 1 null 2\n") {
+		t.Fatalf("nil text changed spacing: %q", buf.String())
+	}
+}
+
+func TestNilArgumentsCtxAndLogAt(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "")
+	ctx := context.Background()
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	var err error
+	l.ErrorCtx(ctx, err)
+	l.LogAt(at, ErrorLevel, err)
+	l.Print(ErrorLevel, err)
+	if buf.Len() != 0 {
+		t.Fatalf("nil error produced output: %q", buf.String())
+	}
+
+	var p *int
+	l.SetNilText("null")
+	l.InfoCtx(ctx, "p=", p)
+	l.LogAt(at, InfoLevel, "p=", p)
+	l.WarnCtx(ctx, err)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], " p=null") || !strings.HasSuffix(lines[1], " p=null") || !strings.HasSuffix(lines[2], " null") {
+		t.Fatalf("nil text not applied: %q", lines)
+	}
+}
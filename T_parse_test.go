@@ -0,0 +1,57 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "db").WithField("user", "alice")
+	setColor(l, true)
+	now := time.Now().Truncate(time.Millisecond)
+	l.now = func() time.Time { return now }
+	l.Info("prime")
+
+	buf.Reset()
+	l.LogKV(WarnLevel, String("q", "select 1"), Int("rows", 3))
+	l.Error("plain message")
+	lines := bytes.SplitAfter(buf.Bytes(), []byte("\n"))
+
+	e, err := ParseLine(string(lines[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Level != WarnLevel || e.Banner != "[db]" || e.Message != "" || !e.Time.Equal(now) {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	want := []Field{String("user", "alice"), String("q", "select 1"), String("rows", "3")}
+	if len(e.Fields) != len(want) {
+		t.Fatalf("got fields %+v", e.Fields)
+	}
+	for i := range want {
+		if e.Fields[i] != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, e.Fields[i], want[i])
+		}
+	}
+
+	e, err = ParseLine(string(lines[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Level != ErrorLevel || e.Message != "plain message" || len(e.Fields) != 1 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	// 重新以 JSON 输出
+	jb := newTestLogger(nil, "").SetFullJSON(true).appendJSON(nil, e, nil)
+	if !bytes.Contains(jb, []byte(`"msg":"plain message"`)) {
+		t.Fatalf("unexpected JSON: %s", jb)
+	}
+
+	if _, err := ParseLine("not a log line"); !errors.Is(err, ErrParseLine) {
+		t.Fatalf("ParseLine(garbage) = %v", err)
+	}
+}
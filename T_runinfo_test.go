@@ -0,0 +1,92 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLogRunContext(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "").SetFullJSON(true)
+	l.LogRunContext(InfoLevel)
+
+	var line struct {
+		Msg    string         `json:"msg"`
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	for _, key := range []string{"cwd", "args", "pid", "go_version"} {
+		if _, ok := line.Fields[key]; !ok {
+			t.Errorf("missing key %q in %q", key, buf.String())
+		}
+	}
+	if _, ok := line.Fields["args"].([]any); !ok {
+		t.Errorf("args is not an array: %q", buf.String())
+	}
+
+	buf.Reset()
+	l.SetLevel(WarnLevel).LogRunContext(InfoLevel)
+	if buf.Len() != 0 {
+		t.Fatalf("disabled level produced output: %q", buf.String())
+	}
+}
+
+func TestDumpDiagnostics(t *testing.T) {
+	l := newTestLogger(new(bytes.Buffer), "app").SetRingBuffer(3)
+	for _, s := range []string{"one", "two", "three", "four"} {
+		l.Info(s)
+	}
+	l.Block(InfoLevel, "five", "six")
+
+	dump := new(bytes.Buffer)
+	if err := l.DumpDiagnostics(dump); err != nil {
+		t.Fatal(err)
+	}
+	out := dump.String()
+	for _, want := range []string{"run context", "pid=", "version=", "config", "level=TRACE"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("header missing %q: %q", want, out)
+		}
+	}
+	recent := out[strings.Index(out, "--- recent logs ---"):]
+	lines := strings.Split(strings.TrimSuffix(recent, "\n"), "\n")[1:]
+	want := []string{"four", "five", "six"}
+	if len(lines) != len(want) {
+		t.Fatalf("got recent lines %q", lines)
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, "[app] "+want[i]) {
+			t.Errorf("recent line %d = %q, want %q", i, line, want[i])
+		}
+	}
+}
+
+func TestWithRuntimeStats(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "").SetFullJSON(true)
+	l.WithRuntimeStats().Error("snapshot")
+	l.Error("plain")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	var line struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &line); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[0], err)
+	}
+	for _, key := range []string{"goroutines", "heap_alloc", "num_gc"} {
+		if _, ok := line.Fields[key].(float64); !ok {
+			t.Errorf("%s is not numeric in %q", key, lines[0])
+		}
+	}
+	if line.Fields["goroutines"].(float64) < 1 || line.Fields["heap_alloc"].(float64) <= 0 {
+		t.Errorf("implausible stats: %v", line.Fields)
+	}
+	if strings.Contains(lines[1], "goroutines") {
+		t.Errorf("stats leaked into the parent logger: %q", lines[1])
+	}
+}
@@ -0,0 +1,46 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestSampled(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "").SetKeySampleRate(0.1)
+
+	in := 0
+	const n = 10000
+	for i := range n {
+		key := "req-" + strconv.Itoa(i)
+		sampled := l.Sampled(key) == l
+		if again := l.Sampled(key) == l; again != sampled {
+			t.Fatalf("key %q sampled inconsistently", key)
+		}
+		if sampled {
+			in++
+		}
+	}
+	if in < n*8/100 || in > n*12/100 {
+		t.Fatalf("sampled %d of %d, want about 10%%", in, n)
+	}
+
+	for i := 0; ; i++ {
+		key := "req-" + strconv.Itoa(i)
+		if sl := l.Sampled(key); sl != l {
+			sl.Error("dropped")
+			sl.LogKV(ErrorLevel, String("k", "v"))
+			sl.Print(InfoLevel, "print")
+			sl.Printf(InfoLevel, "printf %d", 1)
+			if buf.Len() != 0 {
+				t.Fatalf("unsampled logger produced output: %q", buf.String())
+			}
+			break
+		}
+	}
+
+	if l.SetKeySampleRate(1).Sampled("any") != l {
+		t.Fatal("rate 1 must sample every key")
+	}
+}
@@ -0,0 +1,111 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func panicker() {
+	panic("boom")
+}
+
+func TestLogPanic(t *testing.T) {
+	if pkgPrefix != "github.com/Miuzarte/SimpleLog." {
+		t.Fatalf("pkgPrefix = %q", pkgPrefix)
+	}
+
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "sup").WithField("worker", 3)
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				l.LogPanic(r)
+			}
+		}()
+		panicker()
+	}()
+
+	out := buf.String()
+	first, stack, _ := strings.Cut(out, "\n")
+	if !strings.HasPrefix(first, LevelBannerN[PanicLevel]) || !strings.HasSuffix(first, "panic: boom worker=3") {
+		t.Fatalf("unexpected first line: %q", first)
+	}
+	if !strings.HasPrefix(stack, "goroutine ") {
+		t.Fatalf("missing goroutine header: %q", stack)
+	}
+	// 裁剪后的栈应从 panic 发生处开始
+	if frame, _, _ := strings.Cut(strings.SplitN(stack, "\n", 2)[1], "("); frame != pkgPrefix+"panicker" {
+		t.Fatalf("stack not trimmed, first frame %q:\n%s", frame, stack)
+	}
+	if strings.Contains(stack, "runtime/debug.Stack") || strings.Contains(stack, "(*Logger).LogPanic") {
+		t.Fatalf("stack contains logger frames:\n%s", stack)
+	}
+}
+
+func deepPanic(n int) {
+	if n == 0 {
+		panic("deep")
+	}
+	deepPanic(n - 1)
+}
+
+func TestMaxStackDepth(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "").SetMaxStackDepth(3)
+	func() {
+		defer func() { l.LogPanic(recover()) }()
+		deepPanic(20)
+	}()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	// 消息, goroutine 信息, 3 帧各两行, 省略标记
+	if len(lines) != 1+1+3*2+1 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i := 2; i < 8; i += 2 {
+		if !strings.HasPrefix(lines[i], pkgPrefix+"deepPanic(") {
+			t.Errorf("frame line %d = %q", i, lines[i])
+		}
+	}
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "...(") || !strings.HasSuffix(last, " more frames)") {
+		t.Fatalf("missing truncation marker: %q", last)
+	}
+}
+
+func TestShowStackDepth(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "").SetShowStackDepth(true)
+	var recurse func(n int)
+	recurse = func(n int) {
+		l.Info("level")
+		if n > 0 {
+			recurse(n - 1)
+		}
+	}
+	recurse(2)
+
+	var depths []int
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		_, v, ok := strings.Cut(line, " depth=")
+		if !ok {
+			t.Fatalf("no depth field: %q", line)
+		}
+		d, err := strconv.Atoi(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		depths = append(depths, d)
+	}
+	if len(depths) != 3 || depths[1] != depths[0]+1 || depths[2] != depths[1]+1 {
+		t.Fatalf("depths = %v, want increasing by one", depths)
+	}
+
+	buf.Reset()
+	l.SetShowStackDepth(false).Info("off")
+	if strings.Contains(buf.String(), "depth=") {
+		t.Fatalf("depth shown when disabled: %q", buf.String())
+	}
+}
@@ -0,0 +1,32 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSugar(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "sugar")
+	s := l.Sugar()
+	if s.Desugar() != l {
+		t.Fatal("Desugar did not return the original logger")
+	}
+
+	s.Infow("login", "user", "alice", 42, "answer", Bool("ok", true), "dangling")
+	s.Infof("n=%d", 3)
+	s.Desugar().LogKV(WarnLevel, Int("n", 4))
+	l.SetLevel(ErrorLevel)
+	s.Warnw("hidden", "k", "v")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want 3 lines, got %q", lines)
+	}
+	for i, want := range []string{"login user=alice 42=answer ok=true !BADKEY=dangling", "n=3", "n=4"} {
+		if !strings.HasSuffix(lines[i], "[sugar] "+want) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "t")
+	l.Table(InfoLevel, []string{"name", "size", "note"}, [][]string{
+		{"\x1b[1mbold\x1b[m", "1", "x"},
+		{"中文名字", "22", "y"},
+		{"b", "333"},
+	})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines: %q", len(lines), buf.String())
+	}
+	cols := []string{"size", "1", "22", "333"}
+	want := -1
+	for i, line := range lines {
+		col := strings.Index(line, "  "+cols[i]) + 2
+		w := DisplayWidth(line[:col])
+		if want < 0 {
+			want = w
+		} else if w != want {
+			t.Errorf("line %d: column at width %d, want %d: %q", i, w, want, line)
+		}
+		if strings.HasSuffix(line, " ") {
+			t.Errorf("line %d has trailing spaces: %q", i, line)
+		}
+	}
+}
+
+func TestTableJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "t").SetFullJSON(true)
+	l.Table(InfoLevel, []string{"name", "size"}, [][]string{{"a", "1"}, {"b", "2"}})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines: %q", len(lines), buf.String())
+	}
+	var row struct {
+		Fields map[string]string `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &row); err != nil {
+		t.Fatal(err)
+	}
+	if row.Fields["name"] != "b" || row.Fields["size"] != "2" {
+		t.Fatalf("unexpected row: %q", lines[1])
+	}
+}
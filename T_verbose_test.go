@@ -0,0 +1,32 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVerbosity(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "lib").SetLevel(WarnLevel)
+
+	l.V(0).Info("v0")
+	l.V(2).Info("hidden")
+	if got := buf.String(); !strings.Contains(got, " [INFO]") || strings.Contains(got, "hidden") {
+		t.Fatalf("verbosity 0: %q", got)
+	}
+
+	buf.Reset()
+	l.SetVerbosity(2)
+	l.V(1).Infof("v%d", 1)
+	l.V(2).Info("v2")
+	l.V(3).Info("v3")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "[DEBUG]") || !strings.HasSuffix(lines[0], " v1") ||
+		!strings.HasPrefix(lines[1], "[TRACE]") || !strings.HasSuffix(lines[1], " v2") {
+		t.Fatalf("verbosity 2: %q", buf.String())
+	}
+	if !l.V(2).Enabled() || l.V(3).Enabled() {
+		t.Fatal("Enabled does not match verbosity")
+	}
+}
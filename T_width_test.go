@@ -0,0 +1,21 @@
+package SimpleLog
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	for s, want := range map[string]int{
+		"abc":                 3,
+		"\x1b[91mred\x1b[m":   3,
+		"中文":                  4,
+		"e\u0301":             1,
+		"ｆｕｌｌ":                8,
+		"\x1b[1;36m[c]\x1b[m": 3,
+		"\x1b[91m中\x1b[m":     2,
+		"🙂ok":                 4,
+		"":                    0,
+	} {
+		if got := DisplayWidth(s); got != want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// slowWriter 在 release 关闭前阻塞所有写入
+type slowWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	lines   []string
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func TestWriteTimeout(t *testing.T) {
+	w := &slowWriter{release: make(chan struct{})}
+	var errs []error
+	l := newTestLogger(w, "slow").
+		SetWriteTimeout(10 * time.Millisecond).
+		SetOnError(func(err error) { errs = append(errs, err) })
+
+	start := time.Now()
+	l.Info("first")
+	l.Info("second") // 上一次写入仍未返回, 直接丢弃
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("logging blocked for %v", elapsed)
+	}
+	if got := l.Stats().Dropped; got != 2 {
+		t.Fatalf("Dropped = %d, want 2", got)
+	}
+	if len(errs) != 2 || !errors.Is(errs[0], ErrWriteTimeout) || !errors.Is(errs[1], ErrWriteTimeout) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	close(w.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		// 遗留的写入返回后恢复正常
+		l.Info("third")
+		w.mu.Lock()
+		n := len(w.lines)
+		last := ""
+		if n > 0 {
+			last = w.lines[n-1]
+		}
+		w.mu.Unlock()
+		if strings.Contains(last, "third") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("writer did not recover, lines: %q", w.lines)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMaxLineBytes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, "cap").SetMaxLineBytes(64)
+
+	l.Info("short")
+	l.Info(strings.Repeat("长", 40)) // 3 字节的字符, 截断点须落在字符边界
+	l.Block(InfoLevel, "ok", strings.Repeat("x", 100))
+	l.Output(strings.Repeat("y", 100))
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	if !strings.HasSuffix(lines[0], "[cap] short\n") {
+		t.Errorf("short line modified: %q", lines[0])
+	}
+	for i, line := range lines {
+		if len(line) > 64 {
+			t.Errorf("line %d has %d bytes: %q", i, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d split a multibyte character: %q", i, line)
+		}
+	}
+	for _, i := range []int{1, 3} {
+		if !strings.HasSuffix(lines[i], truncatedMarker+"\n") {
+			t.Errorf("line %d missing marker or newline: %q", i, lines[i])
+		}
+	}
+	if !strings.HasSuffix(lines[2], "[cap] ok\n") {
+		t.Errorf("short block line modified: %q", lines[2])
+	}
+	if !strings.HasSuffix(lines[4], truncatedMarker) || len(lines[4]) != 64 {
+		t.Errorf("raw output without newline not truncated: %q", lines[4])
+	}
+}
+
+// flakyWriter broken 为 true 时所有写入失败
+type flakyWriter struct {
+	broken bool
+	bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.broken {
+		return 0, errors.New("disk full")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestWriteCircuitBreaker(t *testing.T) {
+	primary := &flakyWriter{broken: true}
+	fallback := new(bytes.Buffer)
+	meta := new(bytes.Buffer)
+	var errs int
+	l := newTestLogger(primary, "").
+		SetOnError(func(error) { errs++ }).
+		SetMetaOutput(meta).
+		SetWriteCircuitBreaker(3, time.Minute, fallback)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	l.now = func() time.Time { return now }
+
+	for i := range 10 {
+		l.Info("line", i)
+	}
+	if errs != 3 {
+		t.Fatalf("OnError called %d times, want 3", errs)
+	}
+	if out := fallback.String(); !strings.Contains(out, "line2") || !strings.Contains(out, "line9") || strings.Contains(out, "switched") {
+		t.Fatalf("unexpected fallback output: %q", out)
+	}
+	if strings.Count(meta.String(), "switched to fallback") != 1 {
+		t.Fatalf("unexpected meta output: %q", meta.String())
+	}
+
+	// 冷却后重试仍失败, 继续使用备用输出
+	now = now.Add(time.Minute)
+	l.Info("retry")
+	if errs != 3 || !strings.HasSuffix(fallback.String(), "retry\n") {
+		t.Fatalf("failed retry: errs=%d, fallback=%q", errs, fallback.String())
+	}
+
+	primary.broken = false
+	l.Info("still open")
+	if primary.Len() != 0 {
+		t.Fatalf("primary retried before cooldown: %q", primary.String())
+	}
+	now = now.Add(time.Minute)
+	l.Info("back")
+	l.Info("normal")
+	if got := primary.String(); !strings.Contains(got, "back") || !strings.Contains(got, "normal") {
+		t.Fatalf("breaker did not recover: %q", got)
+	}
+	if !strings.Contains(meta.String(), "recovered") {
+		t.Fatalf("recovery not reported: %q", meta.String())
+	}
+}
+
+func TestWriteCircuitBreakerSharedByClones(t *testing.T) {
+	primary := &flakyWriter{broken: true}
+	fallback := new(bytes.Buffer)
+	var errs int
+	l := newTestLogger(primary, "").
+		SetOnError(func(error) { errs++ }).
+		SetMetaOutput(io.Discard).
+		SetWriteCircuitBreaker(3, time.Minute, fallback)
+
+	for i := range 100 {
+		l.WithField("req", i).Info("line")
+	}
+	if errs != 3 {
+		t.Fatalf("OnError called %d times, want 3", errs)
+	}
+	if n := strings.Count(fallback.String(), "\n"); n != 98 {
+		t.Fatalf("%d lines in fallback, want 98", n)
+	}
+}
+
+func TestMetaOutput(t *testing.T) {
+	out := &flakyWriter{broken: true}
+	meta := new(bytes.Buffer)
+	l := newTestLogger(out, "app").SetMetaOutput(meta)
+	l.Info("lost")
+	out.broken = false
+	l.Info("kept")
+
+	if got := out.String(); strings.Contains(got, "disk full") || !strings.Contains(got, "kept") {
+		t.Fatalf("diagnostic leaked into main output: %q", got)
+	}
+	if got := meta.String(); !strings.Contains(got, "[ERROR]") || !strings.Contains(got, "SimpleLog: disk full") {
+		t.Fatalf("write error not reported to meta output: %q", got)
+	}
+}
+
+func TestMetaOutputKeepsDateHeader(t *testing.T) {
+	out, meta := new(bytes.Buffer), new(bytes.Buffer)
+	l := newTestLogger(out, "app").SetMetaOutput(meta).SetShowGap(time.Second)
+	now := time.Date(2026, 2, 28, 9, 0, 0, 0, time.Local)
+	l.now = func() time.Time { return now }
+	l.Info("first")
+	now = time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	l.handleError(errors.New("SimpleLog: disk full"))
+	out.Reset()
+	l.Info("second")
+	if !strings.Contains(out.String(), "[10:00-|03/01]") || !strings.Contains(out.String(), " +") {
+		t.Fatalf("meta output consumed the date header or gap marker: %q", out.String())
+	}
+}
+
+func TestFirstError(t *testing.T) {
+	l := newTestLogger(new(bytes.Buffer), "job")
+	if _, _, _, ok := l.FirstError(); ok {
+		t.Fatal("FirstError reported before any error")
+	}
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	l.now = func() time.Time { return at }
+
+	l.Warn("not an error")
+	var wg sync.WaitGroup
+	l.Error("config missing")
+	at = at.Add(time.Second)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Error("later")
+		}()
+	}
+	wg.Wait()
+
+	level, msg, when, ok := l.FirstError()
+	if !ok || level != ErrorLevel || msg != "config missing" || !when.Equal(at.Add(-time.Second)) {
+		t.Fatalf("FirstError() = %v, %q, %v, %v", level, msg, when, ok)
+	}
+
+	// 派生的实例共享统计
+	l = newTestLogger(io.Discard, "job")
+	l.WithField("req", 1).Error("boom")
+	if _, msg, _, ok := l.FirstError(); !ok || msg != "boom" {
+		t.Fatalf("error from a derived logger missed: %q, %v", msg, ok)
+	}
+	l = newTestLogger(io.Discard, "job")
+	l.Table(ErrorLevel, []string{"id"}, [][]string{{"1"}})
+	if _, _, _, ok := l.FirstError(); !ok {
+		t.Fatal("error-level Table missed")
+	}
+}
+
+func TestWriteTimeoutSharedByClones(t *testing.T) {
+	w := &slowWriter{release: make(chan struct{})}
+	defer close(w.release)
+	l := newTestLogger(w, "slow").
+		SetWriteTimeout(5 * time.Millisecond).
+		SetOnError(func(error) {})
+
+	before := runtime.NumGoroutine()
+	l.Info("stuck")
+	for i := range 50 {
+		l.WithField("i", i).Info("x")
+	}
+	if leaked := runtime.NumGoroutine() - before; leaked > 1 {
+		t.Fatalf("%d goroutines leaked, want at most 1", leaked)
+	}
+	if got := l.Stats().Dropped; got != 51 {
+		t.Fatalf("Dropped = %d, want 51", got)
+	}
+}
+
+func TestWriteTimeoutWithBufferedOutput(t *testing.T) {
+	w := &slowWriter{release: make(chan struct{})}
+	buffered := new(syncBuffer)
+	var errs []error
+	l := newTestLogger(w, "slow").
+		SetWriteTimeout(5*time.Millisecond).
+		SetOnError(func(err error) { errs = append(errs, err) }).
+		AddBufferedOutput(buffered, BufferedInterval(time.Millisecond)).
+		AddJSONOutput(io.Discard)
+
+	l.Info("stuck")
+	if got := l.Stats().Dropped; got != 1 {
+		t.Fatalf("Dropped = %d, want 1 for a line with a JSON output", got)
+	}
+	// 卡住的写入随后会写入缓冲, 此前定时落盘与 Flush 都不能访问缓冲
+	if err := l.Flush(); !errors.Is(err, ErrWriteTimeout) {
+		t.Fatalf("Flush() = %v while a write is pending", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	close(w.release)
+	deadline := time.Now().Add(time.Second)
+	for l.Flush() != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("pending write never finished")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffered.String(), "stuck") {
+		t.Fatalf("buffered output = %q", buffered.String())
+	}
+}
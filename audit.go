@@ -0,0 +1,158 @@
+package SimpleLog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	ErrAuditFormat = errors.New("SimpleLog: audit entry is not a JSON object")
+	ErrAuditChain  = errors.New("SimpleLog: audit chain broken")
+)
+
+// AuditWriter 防篡改的审计日志输出, 配合 AddJSONOutput 或 SetFullJSON 使用,
+// 在每行 JSON 末尾追加 prev_hash 与 hash 两个键:
+//
+//	hash = hex(sha256(prev_hash || 该行去掉这两个键后的内容))
+//
+// 每行的 prev_hash 为上一行的 hash, 链中任意一行被修改, 删除或插入都会被 VerifyAuditLog 发现.
+//
+// 重启后续写同一份日志时, 将上次的 LastHash (或以 LastAuditHash 从已有的日志中读出) 传给 NewAuditWriter,
+// 新旧两段即连成一条链; 若要发现开头被截断, 需另行保存第一行的 prev_hash 并与之比对
+type AuditWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	prev string
+	buf  []byte
+}
+
+// NewAuditWriter prevHash 为链中上一行的 hash, 新的链传入 ""
+func NewAuditWriter(w io.Writer, prevHash string) *AuditWriter {
+	return &AuditWriter{w: w, prev: prevHash}
+}
+
+// LastHash 返回最后一行成功写入的 hash, 用于在重启后续写
+func (a *AuditWriter) LastHash() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.prev
+}
+
+// Write p 中的每一行都须是一个 JSON 对象, 写入失败时链的状态不变
+func (a *AuditWriter) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.buf = a.buf[:0]
+	prev := a.prev
+	for b := p; len(b) > 0; {
+		n := lineLen(b)
+		line := bytes.TrimSuffix(b[:n], []byte("\n"))
+		b = b[n:]
+		if len(line) < 2 || line[0] != '{' || line[len(line)-1] != '}' {
+			return 0, ErrAuditFormat
+		}
+		hash := auditHash(prev, line)
+		a.buf = append(a.buf, line[:len(line)-1]...)
+		a.buf = append(a.buf, `,"prev_hash":"`...)
+		a.buf = append(a.buf, prev...)
+		a.buf = append(a.buf, `","hash":"`...)
+		a.buf = append(a.buf, hash...)
+		a.buf = append(a.buf, "\"}\n"...)
+		prev = hash
+	}
+	if _, err := a.w.Write(a.buf); err != nil {
+		return 0, err
+	}
+	a.prev = prev
+	return len(p), nil
+}
+
+func auditHash(prev string, entry []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prev))
+	h.Write(entry)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// splitAuditLine 将 AuditWriter 输出的一行拆分为原始内容与 prev_hash, hash
+func splitAuditLine(line []byte) (entry []byte, prev, hash string, ok bool) {
+	const prevKey, hashKey = `,"prev_hash":"`, `","hash":"`
+	i := bytes.LastIndex(line, []byte(prevKey))
+	if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return nil, "", "", false
+	}
+	rest := line[i+len(prevKey) : len(line)-2]
+	j := bytes.Index(rest, []byte(hashKey))
+	if j < 0 {
+		return nil, "", "", false
+	}
+	entry = append(bytes.Clone(line[:i]), '}')
+	return entry, string(rest[:j]), string(rest[j+len(hashKey):]), true
+}
+
+// VerifyAuditLog 逐行校验 AuditWriter 输出的哈希链, 返回第一处错误, 包装了 ErrAuditChain.
+// 第一行的 prev_hash 视为链的起点, 不做校验, 空行被忽略
+func VerifyAuditLog(r io.Reader) error {
+	br := bufio.NewReader(r)
+	var prev string
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if verr := verifyAuditLine(bytes.TrimSuffix(line, []byte("\n")), n, &prev); verr != nil {
+				return verr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func verifyAuditLine(line []byte, n int, prev *string) error {
+	if len(line) == 0 {
+		return nil
+	}
+	entry, linePrev, hash, ok := splitAuditLine(line)
+	if !ok {
+		return fmt.Errorf("%w: line %d: missing prev_hash or hash", ErrAuditChain, n)
+	}
+	if n > 1 && linePrev != *prev {
+		return fmt.Errorf("%w: line %d: prev_hash does not match the previous entry", ErrAuditChain, n)
+	}
+	if auditHash(linePrev, entry) != hash {
+		return fmt.Errorf("%w: line %d: hash mismatch", ErrAuditChain, n)
+	}
+	*prev = hash
+	return nil
+}
+
+// LastAuditHash 返回 r 中最后一行的 hash, 用于重启后续写, r 中没有任何记录时返回 ""
+func LastAuditHash(r io.Reader) (string, error) {
+	br := bufio.NewReader(r)
+	var last string
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if line = bytes.TrimSuffix(line, []byte("\n")); len(line) > 0 {
+			_, _, hash, ok := splitAuditLine(line)
+			if !ok {
+				return "", fmt.Errorf("%w: line %d: missing prev_hash or hash", ErrAuditChain, n)
+			}
+			last = hash
+		}
+		if err == io.EOF {
+			return last, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
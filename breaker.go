@@ -0,0 +1,72 @@
+package SimpleLog
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// circuitBreaker 主输出连续失败后切换到备用输出, 冷却后再尝试主输出
+type circuitBreaker struct {
+	failures int
+	cooldown time.Duration
+	fallback io.Writer
+
+	consecutive int
+	open        bool
+	retryAt     time.Time
+}
+
+// SetWriteCircuitBreaker 主输出 (Out) 连续写入失败 failures 次后, 改为写入 fallback,
+// 并向 MetaOutput 输出一条警告, 此后每隔 cooldown 用一行日志尝试主输出, 成功后恢复.
+// 切换期间主输出的错误不再报告, 失败的行同样写入 fallback.
+// fallback 为 nil 时丢弃, failures <= 0 时关闭.
+// 熔断状态由 Clone, WithField 等派生的实例共享, 经由任一实例的失败都会计入
+func (l *Logger) SetWriteCircuitBreaker(failures int, cooldown time.Duration, fallback io.Writer) *Logger {
+	if fallback == nil {
+		fallback = io.Discard
+	}
+	l.Lock()
+	defer l.Unlock()
+	if failures <= 0 {
+		l.breaker = nil
+	} else {
+		l.breaker = &circuitBreaker{failures: failures, cooldown: cooldown, fallback: fallback}
+	}
+	return l
+}
+
+// writeLocked 经由熔断器写入主输出 w, 返回需要报告给 OnError 的错误
+func (cb *circuitBreaker) writeLocked(l *Logger, w io.Writer, level Level, b []byte) error {
+	now := l.timeNow()
+	if cb.open && now.Before(cb.retryAt) {
+		_, err := writeLevel(cb.fallback, level, b)
+		return err
+	}
+
+	err := l.writeLocked(w, level, b)
+	if err == nil {
+		cb.consecutive = 0
+		if cb.open {
+			cb.open = false
+			l.writeMeta(InfoLevel, "SimpleLog: output recovered")
+		}
+		return nil
+	}
+
+	cb.consecutive++
+	if cb.open { // 重试失败, 继续冷却
+		cb.retryAt = now.Add(cb.cooldown)
+		writeLevel(cb.fallback, level, b)
+		return nil
+	}
+	if cb.consecutive < cb.failures {
+		return err
+	}
+	cb.open = true
+	cb.retryAt = now.Add(cb.cooldown)
+	msg := fmt.Sprintf("SimpleLog: output failed %d times, switched to fallback, retry after %s: %v", cb.consecutive, cb.cooldown, err)
+	l.writeMeta(WarnLevel, msg)
+	writeLevel(cb.fallback, level, b)
+	return err
+}
@@ -0,0 +1,61 @@
+package SimpleLog
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Broadcaster 将写入的每行日志转发给所有订阅者, 作为 AddOutput 的输出使用,
+// 适合在网页中实时查看日志 (SSE, WebSocket 等).
+// 订阅者的缓冲写满时丢弃该订阅者的新行, 不会阻塞日志输出
+type Broadcaster struct {
+	mu      sync.Mutex
+	subs    map[chan []byte]struct{}
+	size    int
+	dropped atomic.Uint64
+}
+
+// NewBroadcaster 每个订阅者最多缓冲 size 行
+func NewBroadcaster(size int) *Broadcaster {
+	return &Broadcaster{subs: make(map[chan []byte]struct{}), size: size}
+}
+
+// Subscribe 订阅此后写入的行, 调用 unsubscribe 取消订阅并关闭 ch,
+// unsubscribe 可重复调用
+func (b *Broadcaster) Subscribe() (ch <-chan []byte, unsubscribe func()) {
+	c := make(chan []byte, b.size)
+	b.mu.Lock()
+	b.subs[c] = struct{}{}
+	b.mu.Unlock()
+	var once sync.Once
+	return c, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, c)
+			b.mu.Unlock()
+			close(c)
+		})
+	}
+}
+
+// Dropped 因订阅者缓冲已满而丢弃的行数, 按订阅者累计
+func (b *Broadcaster) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+func (b *Broadcaster) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.subs) == 0 {
+		return len(p), nil
+	}
+	line := append([]byte(nil), p...) // p 来自缓冲池, 所有订阅者共享同一份只读副本
+	for c := range b.subs {
+		select {
+		case c <- line:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+	return len(p), nil
+}
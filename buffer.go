@@ -0,0 +1,222 @@
+package SimpleLog
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// BufferPolicy 单个输出的缓冲策略
+type BufferPolicy struct {
+	size     int
+	interval time.Duration
+	oversize OversizePolicy
+}
+
+// OversizePolicy 单次写入 (一行, 或 Block 的多行) 超过缓冲大小时的处理方式
+type OversizePolicy uint8
+
+const (
+	// OversizeDirect 先 Flush 缓冲中已有的内容, 再将该次写入整体直接写入底层输出,
+	// 先后顺序不变, 且该次写入不会被拆分
+	OversizeDirect OversizePolicy = iota
+	// OversizeTruncate 将其中超过缓冲大小的行截断 (同 SetMaxLineBytes), 再照常写入缓冲
+	OversizeTruncate
+)
+
+// Oversize 返回设置了超长写入处理方式的策略, 默认为 OversizeDirect
+func (p BufferPolicy) Oversize(o OversizePolicy) BufferPolicy {
+	p.oversize = o
+	return p
+}
+
+// Unbuffered 不缓冲, 每行立即写入
+var Unbuffered = BufferPolicy{}
+
+// BufferedSize 缓冲 n 字节, 写满时落盘
+func BufferedSize(n int) BufferPolicy {
+	return BufferPolicy{size: n}
+}
+
+// BufferedInterval 使用默认大小的缓冲, 并每隔 d 落盘一次
+func BufferedInterval(d time.Duration) BufferPolicy {
+	return BufferPolicy{interval: d}
+}
+
+type bufferedWriter struct {
+	*bufio.Writer
+	w        io.Writer
+	stop     chan struct{}
+	oversize OversizePolicy
+	closed   bool // Close 之后不再缓冲, 直接写入底层输出
+}
+
+func (bw *bufferedWriter) Write(p []byte) (int, error) {
+	if bw.closed {
+		if err := bw.Flush(); err != nil {
+			return 0, err
+		}
+		return bw.w.Write(p)
+	}
+	if len(p) <= bw.Size() {
+		return bw.Writer.Write(p)
+	}
+	if bw.oversize == OversizeTruncate {
+		if _, err := bw.Writer.Write(truncateLines(p, bw.Size())); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	return bw.w.Write(p)
+}
+
+// AddBufferedOutput 按策略为 w 添加缓冲后作为额外的输出,
+// 缓冲中的内容在 Flush/Close 时落盘
+func (l *Logger) AddBufferedOutput(w io.Writer, policy BufferPolicy) *Logger {
+	if policy.size == 0 && policy.interval == 0 {
+		return l.AddOutput(w)
+	}
+	bw := &bufferedWriter{w: w, oversize: policy.oversize}
+	if policy.size > 0 {
+		bw.Writer = bufio.NewWriterSize(w, policy.size)
+	} else {
+		bw.Writer = bufio.NewWriter(w)
+	}
+	if policy.interval > 0 {
+		bw.stop = make(chan struct{})
+		go bw.flushLoop(l.logger, policy.interval, bw.stop)
+	}
+	l.Lock()
+	l.buffers = append(l.buffers, bw)
+	l.closed = false
+	l.Unlock()
+	openLoggers.Lock()
+	openLoggers.m[l.logger] = struct{}{}
+	openLoggers.Unlock()
+	return l.AddOutput(bw)
+}
+
+func (bw *bufferedWriter) flushLoop(l *logger, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.Lock()
+			if !l.writing() {
+				bw.Flush()
+			}
+			l.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Flush 将所有缓冲中的内容写入底层输出.
+// 有超时后仍未返回的写入 (见 SetWriteTimeout) 时不访问缓冲, 返回 ErrWriteTimeout
+func (l *Logger) Flush() error {
+	l.Lock()
+	defer l.Unlock()
+	return l.flush()
+}
+
+func (l *logger) flush() error {
+	if l.writing() {
+		return ErrWriteTimeout
+	}
+	var errs []error
+	for _, bw := range l.buffers {
+		if err := bw.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// openLoggers 带有缓冲输出或定时汇总计数, 且尚未 Close 的实例, 供 Shutdown 使用
+var openLoggers = struct {
+	sync.Mutex
+	m map[*logger]struct{}
+}{m: make(map[*logger]struct{})}
+
+// Close 输出尚未汇总的计数, 停止定时落盘并 Flush 所有缓冲, 不会关闭底层输出.
+// 有超时后仍未返回的写入时不访问缓冲, 返回 ErrWriteTimeout
+func (l *Logger) Close() error {
+	l.stopMetrics()
+	openLoggers.Lock()
+	delete(openLoggers.m, l.logger)
+	openLoggers.Unlock()
+	return l.close()
+}
+
+func (l *logger) close() error {
+	l.Lock()
+	defer l.Unlock()
+	writing := l.writing()
+	for _, bw := range l.buffers {
+		if bw.stop != nil {
+			close(bw.stop)
+			bw.stop = nil
+		}
+		if !writing {
+			bw.closed = true
+		}
+	}
+	l.closed = true
+	return l.flush()
+}
+
+var ErrClosed = errors.New("SimpleLog: logger closed")
+
+// PostCloseBehavior Close 之后继续输出日志时的处理方式
+type PostCloseBehavior uint8
+
+const (
+	// PostCloseSync 不再缓冲, 每行同步写入底层输出
+	PostCloseSync PostCloseBehavior = iota
+	// PostCloseDrop 丢弃, 计入 Stats.Dropped 并以 ErrClosed 调用 OnError
+	PostCloseDrop
+)
+
+// SetPostCloseBehavior 设置 Close (或 Shutdown) 之后继续输出日志时的处理方式,
+// 默认为 PostCloseSync. 之后再调用 AddBufferedOutput 会重新启用缓冲
+func (l *Logger) SetPostCloseBehavior(b PostCloseBehavior) *Logger {
+	l.postClose = b
+	return l
+}
+
+// Shutdown 关闭所有带有缓冲输出或定时汇总计数 (SetMetricInterval) 的实例, 同 Close, 须在 main 的开头 defer 调用:
+//
+//	func main() {
+//		defer SimpleLog.Shutdown()
+//		...
+//	}
+//
+// 若此时正在 panic, 先以 PanicLevel 输出 panic 的值与调用栈, 落盘后再重新 panic.
+// 只有直接 defer 时才能捕获 panic, 在其他函数中调用仅执行关闭.
+// 调用 os.Exit 或 Fatal 退出时 defer 不会执行, 此时不会落盘
+func Shutdown() {
+	r := recover()
+	if r != nil {
+		(&Logger{logger: defaultLogger}).LogPanic(r)
+	}
+	openLoggers.Lock()
+	cores := slices.Collect(maps.Keys(openLoggers.m))
+	clear(openLoggers.m)
+	openLoggers.Unlock()
+	for _, l := range cores {
+		l.stopMetrics()
+		l.close()
+	}
+	if r != nil {
+		panic(r)
+	}
+}
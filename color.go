@@ -0,0 +1,71 @@
+package SimpleLog
+
+// SafeLevelColors 不使用闪烁, 反色与背景色的级别颜色, 适合 tmux 与 CI 日志查看器等
+var SafeLevelColors = map[Level]string{
+	TraceLevel: "94",
+	DebugLevel: "92",
+	InfoLevel:  "97",
+	WarnLevel:  "93",
+	ErrorLevel: "91",
+	FatalLevel: "1;91",
+	PanicLevel: "1;95",
+}
+
+// defaultLevelColors 与 LevelBannerC 相同的颜色
+var defaultLevelColors = map[Level]string{
+	TraceLevel: "94",
+	DebugLevel: "92",
+	InfoLevel:  "97",
+	WarnLevel:  "93",
+	ErrorLevel: "91",
+	FatalLevel: "91;5",
+	PanicLevel: "91;5;7",
+}
+
+// SetLevelColors 以 SGR 参数 (如 "91", "1;36") 设置各级别标签与行首标记的颜色,
+// 取代 LevelBannerC 与 LevelGutterC, 如 SetLevelColors(SafeLevelColors), 传入 nil 恢复默认
+func (l *Logger) SetLevelColors(codes map[Level]string) *Logger {
+	l.levelColors = codes
+	return l
+}
+
+// SetANSIReset 设置颜色结束时使用的序列, 默认为 "\x1b[m",
+// 对不支持简写的终端可设为 "\x1b[0m", 传入 "" 恢复默认
+func (l *Logger) SetANSIReset(reset string) *Logger {
+	l.ansiReset = reset
+	return l
+}
+
+func (l *Logger) customColors() bool {
+	return l.levelColors != nil || l.ansiReset != ""
+}
+
+func (l *Logger) ansiResetCode() string {
+	if l.ansiReset == "" {
+		return "\x1b[m"
+	}
+	return l.ansiReset
+}
+
+// appendLevelColored 以级别对应的颜色包裹 s
+func (l *Logger) appendLevelColored(dst []byte, level Level, s string) []byte {
+	codes := l.levelColors
+	if codes == nil {
+		codes = defaultLevelColors
+	}
+	if code := codes[level]; code != "" {
+		dst = append(dst, "\x1b["...)
+		dst = append(dst, code...)
+		dst = append(dst, 'm')
+		dst = append(dst, s...)
+		return append(dst, l.ansiResetCode()...)
+	}
+	return append(dst, s...)
+}
+
+func levelGutterN(level Level) string {
+	if level >= FatalLevel {
+		return "█"
+	}
+	return "▌"
+}
@@ -0,0 +1,161 @@
+package SimpleLog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync/atomic"
+)
+
+var ErrInvalidConfig = errors.New("SimpleLog: invalid config")
+
+// Config 可由 Apply 一次性替换的主要设置
+type Config struct {
+	Level         Level
+	Outputs       []io.Writer // 文本输出, 为空时丢弃文本日志
+	JSONOutputs   []io.Writer // 同 AddJSONOutput
+	Banner        string      // 同 SetBanner
+	Color         bool
+	FullJSON      bool
+	EscapeNewline bool
+	MaxLineBytes  int // 同 SetMaxLineBytes
+}
+
+// Validate 检查 cfg, 不合法时返回包装了 ErrInvalidConfig 的错误
+func (cfg *Config) Validate() error {
+	if cfg.Level < TraceLevel || cfg.Level > PanicLevel {
+		return fmt.Errorf("%w: unknown level %v", ErrInvalidConfig, cfg.Level)
+	}
+	if slices.Contains(cfg.Outputs, nil) || slices.Contains(cfg.JSONOutputs, nil) {
+		return fmt.Errorf("%w: nil output", ErrInvalidConfig)
+	}
+	if _, err := normalizeBanner(cfg.Banner); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if cfg.Color && cfg.FullJSON {
+		return fmt.Errorf("%w: Color has no effect with FullJSON", ErrInvalidConfig)
+	}
+	if cfg.MaxLineBytes < 0 {
+		return fmt.Errorf("%w: negative MaxLineBytes", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Apply 在一次加锁内以 cfg 替换全部对应的设置, 用于热重载配置文件, 可与日志调用并发执行:
+// 每行日志只读取一次 banner, 格式等设置, 不会出现新旧设置混杂的行, Config 也不会观察到中间状态.
+// Outputs 与 JSONOutputs 替换所有已有的输出, 包括 AddBufferedOutput 等添加的输出,
+// 被移除的缓冲仍会在 Close 时落盘.
+// cfg 不合法时保持原有设置不变, 并以 ErrInvalidConfig 调用 OnError
+func (l *Logger) Apply(cfg Config) *Logger {
+	if err := cfg.Validate(); err != nil {
+		l.handleError(err)
+		return l
+	}
+	banner, _ := normalizeBanner(cfg.Banner)
+	l.Lock()
+	defer l.Unlock()
+	l.Level = cfg.Level
+	l.live.level.Store(int64(cfg.Level))
+	l.Out = joinWriters(cfg.Outputs)
+	l.jsonOut = nil
+	if len(cfg.JSONOutputs) > 0 {
+		l.jsonOut = joinWriters(cfg.JSONOutputs)
+	}
+	l.live.json.Store(l.jsonOut != nil)
+	l.maxLineBytes = cfg.MaxLineBytes
+	l.update(func(s *settings) {
+		*s = settings{banner: banner, color: cfg.Color, escapeNewline: cfg.EscapeNewline, fullJSON: cfg.FullJSON}
+	})
+	return l
+}
+
+// Config 返回当前设置的快照, 与 Apply 互斥
+func (l *Logger) Config() Config {
+	l.Lock()
+	defer l.Unlock()
+	s := l.settings()
+	return Config{
+		Level:         Level(l.live.level.Load()), // SetLevel 不加锁, 不能直接读取 l.Level
+		Outputs:       splitWriters(l.Out),
+		JSONOutputs:   splitWriters(l.jsonOut),
+		Banner:        s.banner,
+		Color:         s.color,
+		FullJSON:      s.fullJSON,
+		EscapeNewline: s.escapeNewline,
+		MaxLineBytes:  l.maxLineBytes,
+	}
+}
+
+// settings 实例自身的, 可由 Apply 替换的设置. 发布后不再修改, 变更时整体替换 (写时复制),
+// 日志路径每行只读取一次, 因此同一行内不会混杂新旧设置
+type settings struct {
+	banner        string
+	color         bool
+	escapeNewline bool
+	fullJSON      bool
+	peek          bool // 仅格式化而不输出, 不更新日期与间隔标记的状态, 不由 Apply 管理
+}
+
+var defaultSettings settings
+
+func newView(s *settings) *atomic.Pointer[settings] {
+	view := new(atomic.Pointer[settings])
+	view.Store(s)
+	return view
+}
+
+// settings 返回当前设置, 直接以字面量构造的实例为零值
+func (l *Logger) settings() *settings {
+	if l.view == nil {
+		return &defaultSettings
+	}
+	return l.view.Load()
+}
+
+// update 修改当前设置的副本后整体替换
+func (l *Logger) update(fn func(s *settings)) {
+	s := *l.settings()
+	fn(&s)
+	if l.view == nil {
+		l.view = newView(&s)
+		return
+	}
+	l.view.Store(&s)
+}
+
+// liveConfig 共享配置中由日志路径在锁外读取的部分的副本, 随 SetLevel, AddJSONOutput 与 Apply 同步更新
+type liveConfig struct {
+	level atomic.Int64
+	json  atomic.Bool // 存在 JSON 输出
+}
+
+func (c *liveConfig) clone() *liveConfig {
+	n := new(liveConfig)
+	n.level.Store(c.level.Load())
+	n.json.Store(c.json.Load())
+	return n
+}
+
+func joinWriters(ws []io.Writer) io.Writer {
+	switch len(ws) {
+	case 0:
+		return io.Discard
+	case 1:
+		return ws[0]
+	}
+	return multiWriter(slices.Clone(ws))
+}
+
+func splitWriters(w io.Writer) []io.Writer {
+	switch w := w.(type) {
+	case nil:
+		return nil
+	case multiWriter:
+		return slices.Clone(w)
+	}
+	if w == io.Discard {
+		return nil
+	}
+	return []io.Writer{w}
+}
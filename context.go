@@ -0,0 +1,68 @@
+package SimpleLog
+
+import (
+	"context"
+	"sync"
+)
+
+type contextField struct {
+	name string
+	key  any
+}
+
+var contextFields struct {
+	sync.RWMutex
+	list []contextField
+}
+
+// RegisterContextField 注册一个从 context 中提取的字段,
+// 此后 WithContext 与 *Ctx 方法会以 fieldName 为键附带 ctx.Value(key),
+// ctx 中不存在该键时跳过. 重复注册同名字段会替换原来的 key
+func RegisterContextField(fieldName string, key any) {
+	contextFields.Lock()
+	defer contextFields.Unlock()
+	for i := range contextFields.list {
+		if contextFields.list[i].name == fieldName {
+			contextFields.list[i].key = key
+			return
+		}
+	}
+	contextFields.list = append(contextFields.list, contextField{fieldName, key})
+}
+
+func fieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	contextFields.RLock()
+	defer contextFields.RUnlock()
+	var fields []Field
+	for _, cf := range contextFields.list {
+		if v := ctx.Value(cf.key); v != nil {
+			fields = append(fields, Any(cf.name, v))
+		}
+	}
+	return fields
+}
+
+// WithContext 返回附带 ctx 中已注册字段的副本, 语义同 Clone
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	c := l.Clone()
+	c.fields = append(c.fields, fieldsFromContext(ctx)...)
+	return c
+}
+
+func (l *Logger) logCtx(ctx context.Context, level Level, a []any) {
+	if !l.levelOk(level) {
+		return
+	}
+	if s, ok := l.sprint(level, a); ok {
+		l.log(level, s, fieldsFromContext(ctx))
+	}
+}
+
+func (l *Logger) TraceCtx(ctx context.Context, a ...any) { l.logCtx(ctx, TraceLevel, a) }
+func (l *Logger) DebugCtx(ctx context.Context, a ...any) { l.logCtx(ctx, DebugLevel, a) }
+func (l *Logger) InfoCtx(ctx context.Context, a ...any)  { l.logCtx(ctx, InfoLevel, a) }
+func (l *Logger) WarnCtx(ctx context.Context, a ...any)  { l.logCtx(ctx, WarnLevel, a) }
+func (l *Logger) ErrorCtx(ctx context.Context, a ...any) { l.logCtx(ctx, ErrorLevel, a) }
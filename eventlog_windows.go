@@ -0,0 +1,63 @@
+//go:build windows
+
+package SimpleLog
+
+import (
+	"strings"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLog *eventlog.Log 中用到的部分, 便于测试替换
+type eventLog interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+	Close() error
+}
+
+// EventLogWriter 将每行日志写入 Windows 事件日志, 实现了 LevelWriter:
+// ErrorLevel 及以上为错误, WarnLevel 为警告, 其余为信息.
+// 事件查看器不解析 ANSI 颜色, 应搭配关闭颜色的实例使用
+type EventLogWriter struct {
+	log     eventLog
+	EventID uint32
+}
+
+// NewEventLogWriter 打开名为 source 的事件源.
+// 事件源须事先注册, 如以管理员权限调用
+// eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info),
+// 未注册的事件源仍可写入, 但事件查看器会提示找不到事件描述
+func NewEventLogWriter(source string) (*EventLogWriter, error) {
+	l, err := eventlog.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	return &EventLogWriter{log: l, EventID: 1}, nil
+}
+
+// Write 不带级别的内容按信息写入
+func (w *EventLogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(InfoLevel, p)
+}
+
+func (w *EventLogWriter) WriteLevel(level Level, p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	var err error
+	switch {
+	case level >= ErrorLevel:
+		err = w.log.Error(w.EventID, msg)
+	case level == WarnLevel:
+		err = w.log.Warning(w.EventID, msg)
+	default:
+		err = w.log.Info(w.EventID, msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *EventLogWriter) Close() error {
+	return w.log.Close()
+}
@@ -0,0 +1,41 @@
+package SimpleLog
+
+import "os"
+
+// SetExitFunc 设置 Fatal 输出后调用的退出函数, 默认为 os.Exit, 传入 nil 恢复默认,
+// 可用于测试或在退出前执行清理
+func (l *Logger) SetExitFunc(exit func(code int)) *Logger {
+	if exit == nil {
+		exit = os.Exit
+	}
+	l.exit = exit
+	return l
+}
+
+// SetFatalExitCode 设置 Fatal 的退出码, 默认为 1
+func (l *Logger) SetFatalExitCode(code int) *Logger {
+	l.exitCode = code
+	return l
+}
+
+// SetExitCodeFunc 设置按级别计算退出码的函数, 非 nil 时优先于 SetFatalExitCode,
+// 目前只有 Fatal 会退出, level 恒为 FatalLevel
+func (l *Logger) SetExitCodeFunc(fn func(level Level) int) *Logger {
+	l.exitCodeFunc = fn
+	return l
+}
+
+// fatal 输出并落盘后退出. 同一实例 (及其 Clone) 只有第一次 Fatal 会执行,
+// 并发的其他 Fatal 阻塞至退出, 以免重复退出或输出交错;
+// 若退出函数返回了, 此后的 Fatal 直接返回而不输出
+func (l *Logger) fatal(print func()) {
+	l.fatalOnce.Do(func() {
+		print()
+		l.Flush()
+		code := l.exitCode
+		if l.exitCodeFunc != nil {
+			code = l.exitCodeFunc(FatalLevel)
+		}
+		l.exit(code)
+	})
+}
@@ -0,0 +1,248 @@
+package SimpleLog
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type fieldKind uint8
+
+const (
+	stringKind fieldKind = iota
+	intKind
+	boolKind
+	durationKind
+	anyKind
+	lazyKind
+	hexKind
+	base64Kind
+)
+
+// Field 带类型的键值对, 常见类型不经过 interface 装箱
+type Field struct {
+	Key   string
+	kind  fieldKind
+	scope FieldScope
+	str   string
+	num   int64
+	val   any
+}
+
+func String(key, value string) Field {
+	return Field{Key: key, kind: stringKind, str: value}
+}
+
+func Int(key string, value int) Field {
+	return Field{Key: key, kind: intKind, num: int64(value)}
+}
+
+func Bool(key string, value bool) Field {
+	f := Field{Key: key, kind: boolKind}
+	if value {
+		f.num = 1
+	}
+	return f
+}
+
+func Dur(key string, value time.Duration) Field {
+	return Field{Key: key, kind: durationKind, num: int64(value)}
+}
+
+// Any 任意类型的字段, 以 fmt.Sprint 渲染
+func Any(key string, value any) Field {
+	return Field{Key: key, kind: anyKind, val: value}
+}
+
+// Hex 以小写十六进制输出的字节, 适合哈希值等, 过长时可配合 SetMaxLineBytes 截断
+func Hex(key string, b []byte) Field {
+	return Field{Key: key, kind: hexKind, val: b}
+}
+
+// Base64 以标准 base64 编码输出的字节
+func Base64(key string, b []byte) Field {
+	return Field{Key: key, kind: base64Kind, val: b}
+}
+
+// FieldScope 字段出现在哪种格式的输出中
+type FieldScope uint8
+
+const (
+	ScopeBoth     FieldScope = iota // 文本与 JSON 中都输出, 默认
+	ScopeTextOnly                   // 仅在文本中输出
+	ScopeJSONOnly                   // 仅在 JSON 中输出, 如较大的原始数据, 以免干扰控制台
+)
+
+// Scope 返回限定了输出范围的字段, 同时输出文本与 JSON 的实例可借此让同一行在两边显示不同的字段
+//
+//	logger.LogKV(slog.InfoLevel, slog.String("req", id), slog.Any("payload", body).Scope(slog.ScopeJSONOnly))
+func (f Field) Scope(s FieldScope) Field {
+	f.scope = s
+	return f
+}
+
+// appendBytes 按 hexKind 或 base64Kind 编码追加 f 的字节
+func (f *Field) appendBytes(dst []byte) []byte {
+	b := f.val.([]byte)
+	if f.kind == hexKind {
+		return hex.AppendEncode(dst, b)
+	}
+	return base64.StdEncoding.AppendEncode(dst, b)
+}
+
+// Lazy 延迟求值的字段, fn 仅在该行通过级别判断与 filter, 确定输出时才会调用, 且只调用一次
+func Lazy(key string, fn func() any) Field {
+	return Field{Key: key, kind: lazyKind, val: fn}
+}
+
+func hasLazy(fields []Field) bool {
+	return slices.ContainsFunc(fields, func(f Field) bool { return f.kind == lazyKind })
+}
+
+func hasDuration(fields []Field) bool {
+	return slices.ContainsFunc(fields, func(f Field) bool { return f.isDuration() })
+}
+
+func (f *Field) isDuration() bool {
+	if f.kind == anyKind {
+		_, ok := f.val.(time.Duration)
+		return ok
+	}
+	return f.kind == durationKind
+}
+
+// needResolve 是否需要先经 resolveFields 处理
+func needResolve(fields []Field, precision time.Duration) bool {
+	return hasLazy(fields) || precision > 0 && hasDuration(fields)
+}
+
+// resolveFields 返回对 Lazy 字段求值, 并将时长舍入到 precision 后的新切片
+func resolveFields(fields []Field, precision time.Duration) []Field {
+	resolved := slices.Clone(fields)
+	for i := range resolved {
+		f := &resolved[i]
+		if f.kind == lazyKind {
+			*f = Any(f.Key, f.val.(func() any)()).Scope(f.scope)
+		}
+		if precision > 0 && f.isDuration() {
+			if f.kind == anyKind {
+				*f = Dur(f.Key, f.val.(time.Duration)).Scope(f.scope)
+			}
+			f.num = int64(time.Duration(f.num).Round(precision))
+		}
+	}
+	return resolved
+}
+
+// FieldQuoting 文本格式中字段值的加引号方式, 仅作用于字符串与 Any 字段
+type FieldQuoting uint8
+
+const (
+	QuoteMinimal FieldQuoting = iota // 仅在含有空白, 引号, 等号或控制字符时加引号
+	QuoteAlways                      // 总是加引号
+	QuoteNever                       // 从不加引号, 也不转义, 值中的换行等字符会原样输出
+)
+
+// quoteFunc 返回判断是否需要加引号的函数, elem 为 true 时用于切片元素, nil 表示不加引号
+func (q FieldQuoting) quoteFunc(elem bool) func(rune) bool {
+	switch q {
+	case QuoteAlways:
+		return quoteAlways
+	case QuoteNever:
+		return nil
+	}
+	if elem {
+		return needQuoteElem
+	}
+	return needQuote
+}
+
+// SetFieldQuoting 设置文本格式中字段值的加引号方式, 默认为 QuoteMinimal
+func (l *Logger) SetFieldQuoting(q FieldQuoting) *Logger {
+	l.quoting = q
+	return l
+}
+
+// appendText 以 key=value 的形式追加到 dst
+func (f *Field) appendText(dst []byte, q FieldQuoting) []byte {
+	dst = append(dst, f.Key...)
+	dst = append(dst, '=')
+	switch f.kind {
+	case stringKind:
+		dst = appendTextQuoted(dst, f.str, q.quoteFunc(false))
+	case intKind:
+		dst = strconv.AppendInt(dst, f.num, 10)
+	case boolKind:
+		dst = strconv.AppendBool(dst, f.num != 0)
+	case durationKind:
+		dst = append(dst, time.Duration(f.num).String()...)
+	case anyKind:
+		dst = appendTextAny(dst, f.val, q, false)
+	case hexKind, base64Kind:
+		dst = f.appendBytes(dst)
+	}
+	return dst
+}
+
+// appendTextAny 追加任意值:
+//   - []byte 以标准 base64 编码, 与 JSON 一致
+//   - time.Time 使用 RFC3339Nano, 与 JSON 一致
+//   - 实现了 fmt.Stringer 或 error 的值使用其字符串形式
+//   - 切片与数组渲染为 [a,b,c], 逐个元素递归, QuoteMinimal 下含逗号或方括号的元素加引号
+//   - 其余值使用 fmt.Sprint
+func appendTextAny(dst []byte, v any, q FieldQuoting, elem bool) []byte {
+	switch v := v.(type) {
+	case []byte:
+		return base64.StdEncoding.AppendEncode(dst, v)
+	case time.Time:
+		return appendTextQuoted(dst, v.Format(time.RFC3339Nano), q.quoteFunc(elem))
+	case fmt.Stringer, error:
+		return appendTextQuoted(dst, fmt.Sprint(v), q.quoteFunc(elem))
+	}
+	rv := reflect.ValueOf(v)
+	if kind := rv.Kind(); kind == reflect.Slice || kind == reflect.Array {
+		dst = append(dst, '[')
+		for i := range rv.Len() {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendTextAny(dst, rv.Index(i).Interface(), q, true)
+		}
+		return append(dst, ']')
+	}
+	return appendTextQuoted(dst, fmt.Sprint(v), q.quoteFunc(elem))
+}
+
+func needQuoteElem(r rune) bool {
+	return needQuote(r) || r == ',' || r == '[' || r == ']'
+}
+
+// appendTextQuoted quote 为 nil 时原样追加, 否则在 s 为空或含有 quote 判定的字符时加引号
+func appendTextQuoted(dst []byte, s string, quote func(rune) bool) []byte {
+	if quote == nil {
+		return append(dst, s...)
+	}
+	if len(s) == 0 || strings.ContainsFunc(s, quote) {
+		return strconv.AppendQuote(dst, s)
+	}
+	return append(dst, s...)
+}
+
+func needQuote(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == 0x7f
+}
+
+func quoteAlways(rune) bool { return true }
+
+// LogKV 输出一行仅由 Field 组成的日志
+func (l *Logger) LogKV(level Level, kvs ...Field) {
+	if !l.levelOk(level) {
+		return
+	}
+	l.log(level, "", kvs)
+}
@@ -0,0 +1,148 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// maxFrameSize ReadFrame 接受的最大帧长度, 防止损坏的数据导致分配过多内存
+const maxFrameSize = 16 << 20
+
+var (
+	ErrFrameTooLarge = errors.New("SimpleLog: frame too large")
+	ErrInvalidFrame  = errors.New("SimpleLog: invalid frame")
+)
+
+// frameWriter 为每行 JSON 加上 4 字节大端序的长度前缀, 去掉行末的换行符
+type frameWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewFrameWriter 返回以长度前缀分帧的输出, 配合 AddJSONOutput 或 SetFullJSON 使用,
+// 每条日志为一帧: 4 字节大端序的长度, 随后是该长度的 JSON, 读取端以 ReadFrame 解码.
+// 由 Block 一次写入的多行会拆分为多帧, 在同一次 Write 中写入 w
+func NewFrameWriter(w io.Writer) io.Writer {
+	return &frameWriter{w: w}
+}
+
+func (fw *frameWriter) Write(p []byte) (int, error) {
+	fw.buf = fw.buf[:0]
+	for b := p; len(b) > 0; {
+		n := lineLen(b)
+		line := bytes.TrimSuffix(b[:n], []byte("\n"))
+		fw.buf = binary.BigEndian.AppendUint32(fw.buf, uint32(len(line)))
+		fw.buf = append(fw.buf, line...)
+		b = b[n:]
+	}
+	if _, err := fw.w.Write(fw.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// jsonEvent appendJSON 输出的结构
+type jsonEvent struct {
+	LevelNum Level           `json:"level_num"`
+	Time     time.Time       `json:"time"`
+	Banner   string          `json:"banner"`
+	Caller   Caller          `json:"caller"`
+	Fields   json.RawMessage `json:"fields"`
+	Msg      string          `json:"msg"`
+}
+
+// ReadFrame 从 r 读取一帧并解码为 Event, r 中没有更多帧时返回 io.EOF.
+// 字段保持原有顺序, 字符串, 整数与布尔值还原为对应类型的字段, 其余值以 Any 保存;
+// Dur 字段在 JSON 中为字符串, 解码后为 String 字段, 调用栈不会保留
+func ReadFrame(r io.Reader) (*Event, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxFrameSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF { // 已读到长度, 帧不完整
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	var je jsonEvent
+	if err := json.Unmarshal(payload, &je); err != nil {
+		return nil, err
+	}
+	fields, err := decodeFields(je.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		Time:    je.Time,
+		Level:   je.LevelNum,
+		Banner:  je.Banner,
+		Message: je.Msg,
+		Fields:  fields,
+		Caller:  je.Caller,
+	}, nil
+}
+
+// decodeFields 按顺序解码 fields 对象
+func decodeFields(raw json.RawMessage) ([]Field, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil { // null
+		return nil, nil
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("%w: fields is not an object", ErrInvalidFrame)
+	}
+	var fields []Field
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid field key %v", ErrInvalidFrame, tok)
+		}
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		fields = append(fields, decodeField(key, v))
+	}
+	return fields, nil
+}
+
+func decodeField(key string, v any) Field {
+	switch v := v.(type) {
+	case string:
+		return String(key, v)
+	case bool:
+		return Bool(key, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil && int64(int(i)) == i {
+			return Int(key, int(i))
+		}
+		if f, err := v.Float64(); err == nil {
+			return Any(key, f)
+		}
+	}
+	return Any(key, v)
+}
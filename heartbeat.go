@@ -0,0 +1,53 @@
+package SimpleLog
+
+import (
+	"sync"
+	"time"
+)
+
+// processStart 用于计算 uptime
+var processStart = time.Now()
+
+// SetHeartbeatQuietPeriod 最近 d 内已有其他日志时, 跳过该次心跳, 传入 0 (默认) 总是输出
+func (l *Logger) SetHeartbeatQuietPeriod(d time.Duration) *Logger {
+	l.hbQuiet = d
+	return l
+}
+
+// StartHeartbeat 每隔 interval 以 level 输出一行 msg, 附带 uptime 与 WithRuntimeStats 中的字段,
+// 表明进程仍在运行, 适合长时间空闲的服务. 返回的 stop 停止心跳, 返回后不会再输出, 可重复调用
+func (l *Logger) StartHeartbeat(interval time.Duration, level Level, msg string) (stop func()) {
+	c, stopTicker := l.newTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		defer stopTicker()
+		var own uint64 // 心跳自身输出后的行数, 此后有变化说明有其他日志
+		for {
+			select {
+			case <-c:
+				now := l.timeNow()
+				if l.hbQuiet > 0 && l.counters.lines.Load() != own && now.Sub(time.Unix(0, l.counters.last.Load())) < l.hbQuiet {
+					continue
+				}
+				if !l.levelOk(level) {
+					continue
+				}
+				l.logAt(now, level, msg, heartbeatFields(now))
+				own = l.counters.lines.Load()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-exited
+	}
+}
+
+func heartbeatFields(now time.Time) []Field {
+	return append([]Field{Dur("uptime", now.Sub(processStart).Round(time.Second))}, runtimeStats()...)
+}
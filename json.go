@@ -0,0 +1,153 @@
+package SimpleLog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// appendJSON 以单行 JSON 对象追加 e, 键固定为:
+//
+//	level      级别名, 如 "INFO"
+//	level_num  级别数值
+//	time       RFC3339Nano 时间戳
+//	banner     banner
+//	caller     {"file", "line", "func"}
+//	fields     {key: value}
+//	msg        消息, 开启 SetOmitEmptyMessage 时消息为空则省略
+//	stack      调用栈, 仅 LogPanic 等带有调用栈时出现
+func (l *Logger) appendJSON(dst []byte, e *Event, stack []byte) []byte {
+	dst = append(dst, `{"level":`...)
+	dst = appendJSONString(dst, e.Level.String())
+	dst = append(dst, `,"level_num":`...)
+	dst = strconv.AppendInt(dst, int64(e.Level), 10)
+	dst = append(dst, `,"time":"`...)
+	dst = e.Time.AppendFormat(dst, time.RFC3339Nano)
+	dst = append(dst, `","banner":`...)
+	dst = appendJSONString(dst, e.Banner)
+	dst = append(dst, `,"caller":{"file":`...)
+	dst = appendJSONString(dst, e.Caller.File)
+	dst = append(dst, `,"line":`...)
+	dst = strconv.AppendInt(dst, int64(e.Caller.Line), 10)
+	dst = append(dst, `,"func":`...)
+	dst = appendJSONString(dst, e.Caller.Func)
+	dst = append(dst, `},"fields":{`...)
+	first := true
+	for i := range e.Fields {
+		if e.Fields[i].scope == ScopeTextOnly {
+			continue
+		}
+		if !first {
+			dst = append(dst, ',')
+		}
+		first = false
+		dst = e.Fields[i].appendJSON(dst)
+	}
+	dst = append(dst, '}')
+	if len(e.Message) > 0 || !l.omitEmptyMsg {
+		dst = append(dst, `,"msg":`...)
+		dst = appendJSONString(dst, e.Message)
+	}
+	if len(stack) > 0 {
+		dst = append(dst, `,"stack":`...)
+		dst = appendJSONString(dst, string(stack))
+	}
+	return append(dst, "}\n"...)
+}
+
+// SetOmitEmptyMessage 消息为空时 JSON 中不输出 msg 键, 用于只有字段的结构化记录,
+// 文本格式本就不输出空消息. 与 SetSkipEmpty 同时开启时, 既无消息也无字段的行仍会被跳过
+func (l *Logger) SetOmitEmptyMessage(omit bool) *Logger {
+	l.omitEmptyMsg = omit
+	return l
+}
+
+// AddJSONOutput 添加一个 JSON 输出, 无论实例本身以何种格式输出,
+// 每行日志都会以 SetFullJSON 的格式同时写入 w
+func (l *Logger) AddJSONOutput(w io.Writer) *Logger {
+	if mw, ok := l.jsonOut.(multiWriter); ok {
+		l.jsonOut = append(slices.Clip(mw), w)
+	} else if l.jsonOut != nil {
+		l.jsonOut = multiWriter{l.jsonOut, w}
+	} else {
+		l.jsonOut = w
+	}
+	l.live.json.Store(true)
+	return l
+}
+
+// appendJSON 以 "key":value 的形式追加到 dst
+func (f *Field) appendJSON(dst []byte) []byte {
+	dst = appendJSONString(dst, f.Key)
+	dst = append(dst, ':')
+	switch f.kind {
+	case stringKind:
+		dst = appendJSONString(dst, f.str)
+	case intKind:
+		dst = strconv.AppendInt(dst, f.num, 10)
+	case boolKind:
+		dst = strconv.AppendBool(dst, f.num != 0)
+	case durationKind:
+		dst = appendJSONString(dst, time.Duration(f.num).String())
+	case anyKind:
+		dst = appendJSONValue(dst, f.val)
+	case hexKind, base64Kind:
+		dst = append(dst, '"')
+		dst = f.appendBytes(dst)
+		dst = append(dst, '"')
+	}
+	return dst
+}
+
+// appendJSONValue time.Duration 与 Dur 字段一致渲染为字符串, 其余值使用 json.Marshal
+func appendJSONValue(dst []byte, v any) []byte {
+	if d, ok := v.(time.Duration); ok {
+		return appendJSONString(dst, d.String())
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(dst, fmt.Sprint(v))
+	}
+	return append(dst, b...)
+}
+
+const hexDigits = "0123456789abcdef"
+
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				dst = append(dst, `�`...)
+			} else {
+				dst = append(dst, s[i:i+size]...)
+			}
+			i += size
+			continue
+		}
+		switch c {
+		case '"', '\\':
+			dst = append(dst, '\\', c)
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			if c < 0x20 {
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			} else {
+				dst = append(dst, c)
+			}
+		}
+		i++
+	}
+	return append(dst, '"')
+}
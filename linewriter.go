@@ -0,0 +1,80 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+)
+
+// lineWriter 按行拆分写入的内容, 每行作为一条日志输出
+type lineWriter struct {
+	mu    sync.Mutex
+	l     *Logger
+	level Level
+	tag   string
+	buf   []byte // 尚未遇到换行符的部分
+}
+
+// Writer 返回一个按行输出日志的 io.Writer, 适合接管子进程或第三方库的输出,
+// 不以换行符结尾的内容会暂存到下一次写入, Close 时输出剩余部分
+func (l *Logger) Writer(level Level) io.WriteCloser {
+	return &lineWriter{l: l, level: level}
+}
+
+// TaggedWriter 同 Writer, 但在每行消息前加上 tag, 如 "[worker-3]",
+// 便于多个子进程共用一个实例时区分来源
+func (l *Logger) TaggedWriter(level Level, tag string) io.WriteCloser {
+	return &lineWriter{l: l, level: level, tag: tag}
+}
+
+// StdLogger 返回一个经由 Writer(level) 输出的 *log.Logger, 前缀与 flags 均为空,
+// 用于需要 *log.Logger 的旧代码或第三方库
+func (l *Logger) StdLogger(level Level) *log.Logger {
+	return log.New(l.Writer(level), "", 0)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(p)
+	for {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			break
+		}
+		if len(w.buf) > 0 {
+			w.buf = append(w.buf, p[:i]...)
+			w.emit(w.buf)
+			w.buf = w.buf[:0]
+		} else {
+			w.emit(p[:i])
+		}
+		p = p[i+1:]
+	}
+	w.buf = append(w.buf, p...)
+	return n, nil
+}
+
+// Close 输出末尾不完整的行
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *lineWriter) emit(line []byte) {
+	if !w.l.levelOk(w.level) {
+		return
+	}
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	s := string(line)
+	if w.tag != "" {
+		s = w.tag + " " + s
+	}
+	w.l.log(w.level, s, nil)
+}
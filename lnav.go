@@ -0,0 +1,33 @@
+package SimpleLog
+
+// lnavLevels lnav 能够识别的级别名称
+var lnavLevels = [...]string{"TRACE", "DEBUG", "INFO", "WARNING", "ERROR", "FATAL", "CRITICAL"}
+
+// lnavTimeLayout ISO 8601 时间戳, 精确到毫秒并带时区
+const lnavTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// SetLnavCompatible 以 lnav 内置格式可以识别的布局输出文本日志:
+//
+//	2006-01-02T15:04:05.000+08:00 WARNING [banner] message key=value
+//
+// 即 ISO 8601 时间戳, 级别名称 (TRACE, DEBUG, INFO, WARNING, ERROR, FATAL, PanicLevel 为 CRITICAL),
+// banner (为空时省略), 消息与字段, 以单个空格分隔. 不输出颜色, 行首标记与间隔标记, JSON 输出不受影响
+func (l *Logger) SetLnavCompatible(lnav bool) *Logger {
+	l.lnav = lnav
+	return l
+}
+
+func (l *Logger) appendLnav(cfg *settings, dst []byte, e *Event) []byte {
+	dst = e.Time.AppendFormat(dst, lnavTimeLayout)
+	dst = append(dst, ' ')
+	if e.Level >= TraceLevel && e.Level <= PanicLevel {
+		dst = append(dst, lnavLevels[e.Level]...)
+	} else {
+		dst = append(dst, e.Level.String()...)
+	}
+	if len(e.Banner) > 0 {
+		dst = append(dst, ' ')
+		dst = append(dst, e.Banner...)
+	}
+	return l.appendBody(cfg, dst, e)
+}
@@ -0,0 +1,123 @@
+package SimpleLog
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Counter 由 Counter 创建的计数器, 增量按 SetMetricInterval 设置的间隔汇总为一行输出,
+// 可并发使用
+type Counter struct {
+	name    string
+	total   atomic.Int64
+	flushed int64 // 上次汇总时的 total, 由 metricSet.mu 保护
+}
+
+func (c *Counter) Inc() {
+	c.total.Add(1)
+}
+
+func (c *Counter) Add(n int) {
+	c.total.Add(int64(n))
+}
+
+// Value 返回累计值
+func (c *Counter) Value() int64 {
+	return c.total.Load()
+}
+
+// metricSet 共享配置中的全部计数器, 与 Clone 出的实例共享
+type metricSet struct {
+	mu       sync.Mutex
+	counters []*Counter
+	out      *Logger // 最后一次调用 SetMetricInterval 的实例, 汇总行以其 banner 等设置输出
+	stop     chan struct{}
+}
+
+// Counter 返回名为 name 的计数器, 同名的返回同一个, 在共享同一配置的实例以及 Clone 出的实例之间共享.
+// 未设置 SetMetricInterval 时只累计, 不输出
+func (l *Logger) Counter(name string) *Counter {
+	ms := l.metrics
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if i := slices.IndexFunc(ms.counters, func(c *Counter) bool { return c.name == name }); i >= 0 {
+		return ms.counters[i]
+	}
+	c := &Counter{name: name}
+	ms.counters = append(ms.counters, c)
+	return c
+}
+
+// SetMetricInterval 每隔 d 以 InfoLevel 输出一行 "metrics", 以 name=N 的字段列出各计数器在该间隔内的增量,
+// 没有变化的计数器省略, 全部没有变化时不输出. 传入 0 停止, Close 与 Shutdown 时输出剩余的增量
+func (l *Logger) SetMetricInterval(d time.Duration) *Logger {
+	ms := l.metrics
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.stop != nil {
+		close(ms.stop)
+		ms.stop = nil
+	}
+	ms.out = l
+	if d > 0 {
+		ms.stop = make(chan struct{})
+		c, stop := l.newTicker(d)
+		go ms.loop(c, stop, ms.stop)
+		openLoggers.Lock()
+		openLoggers.m[l.logger] = struct{}{}
+		openLoggers.Unlock()
+	}
+	return l
+}
+
+func (l *logger) newTicker(d time.Duration) (<-chan time.Time, func()) {
+	if l.tick != nil {
+		return l.tick(d)
+	}
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+func (ms *metricSet) loop(c <-chan time.Time, stopTicker func(), stop <-chan struct{}) {
+	defer stopTicker()
+	for {
+		select {
+		case <-c:
+			ms.flush()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// flush 输出自上次以来的增量
+func (ms *metricSet) flush() {
+	ms.mu.Lock()
+	var fields []Field
+	for _, c := range ms.counters {
+		total := c.total.Load()
+		if delta := total - c.flushed; delta != 0 {
+			fields = append(fields, Int(c.name, int(delta)))
+			c.flushed = total
+		}
+	}
+	out := ms.out
+	ms.mu.Unlock()
+	if len(fields) > 0 && out != nil && out.levelOk(InfoLevel) {
+		out.log(InfoLevel, "metrics", fields)
+	}
+}
+
+// stopMetrics 停止定时汇总并输出剩余的增量
+func (l *logger) stopMetrics() {
+	ms := l.metrics
+	ms.mu.Lock()
+	if ms.stop != nil {
+		close(ms.stop)
+		ms.stop = nil
+	}
+	ms.mu.Unlock()
+	ms.flush()
+}
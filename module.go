@@ -0,0 +1,54 @@
+package SimpleLog
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// moduleLevels banner (去掉方括号) 到级别的覆盖, 写时复制, 读取时无需加锁
+var (
+	moduleLevels   atomic.Pointer[map[string]Level]
+	moduleLevelsMu sync.Mutex
+)
+
+// SetModuleLevel 覆盖所有 banner 为 banner 的实例的级别, 含此后创建的实例,
+// 用于在运行时单独调整某个子系统, banner 两侧的方括号可省略.
+// 覆盖优先于 SetLevel, 但设置了 SetEnabler 的实例仍以 enabler 为准
+func SetModuleLevel(banner string, level Level) {
+	updateModuleLevels(func(m map[string]Level) { m[trimBanner(banner)] = level })
+}
+
+// ClearModuleLevel 取消 SetModuleLevel 的覆盖
+func ClearModuleLevel(banner string) {
+	updateModuleLevels(func(m map[string]Level) { delete(m, trimBanner(banner)) })
+}
+
+func updateModuleLevels(update func(m map[string]Level)) {
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	m := make(map[string]Level)
+	if old := moduleLevels.Load(); old != nil {
+		maps.Copy(m, *old)
+	}
+	update(m)
+	if len(m) == 0 {
+		moduleLevels.Store(nil)
+	} else {
+		moduleLevels.Store(&m)
+	}
+}
+
+func moduleLevel(banner string) (Level, bool) {
+	m := moduleLevels.Load()
+	if m == nil {
+		return 0, false
+	}
+	level, ok := (*m)[trimBanner(banner)]
+	return level, ok
+}
+
+func trimBanner(banner string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(banner, "["), "]")
+}
@@ -0,0 +1,78 @@
+package SimpleLog
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SetNilText 设置文本格式中 nil 值的输出, 默认为 fmt 的 "<nil>", 传入 "" 恢复默认.
+// 作用于 Print 系列方法 (Info, Error, LogAt, InfoCtx 等) 的参数与 Any 字段,
+// nil 指针, map, chan, func 等带类型的 nil 同样视为 nil, 格式化方法 (Infof 等) 不受影响
+func (l *Logger) SetNilText(text string) *Logger {
+	l.nilText = text
+	return l
+}
+
+// WithError 返回附带 error 字段的副本, 语义同 WithField; err 为 nil 时直接返回 l
+func (l *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return l
+	}
+	return l.WithField("error", err)
+}
+
+// isNil 判断 v 是否为 nil 或带类型的 nil
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// sprint 格式化 Print 系列方法的参数, 所有以 ...any 为参数的方法都经由此处:
+// 以 SetNilText 替换 nil 参数, ErrorLevel 的参数全部为 nil 时返回 false, 即不输出
+func (l *Logger) sprint(level Level, a []any) (string, bool) {
+	if level == ErrorLevel && allNil(a) {
+		return "", false
+	}
+	return fmt.Sprint(l.replaceNil(a)...), true
+}
+
+// allNil a 非空且全部为 nil, 如 Error(err) 中 err 为 nil
+func allNil(a []any) bool {
+	for _, v := range a {
+		if !isNil(v) {
+			return false
+		}
+	}
+	return len(a) > 0
+}
+
+// nilToken 替换 nil 参数的文本. 不是字符串类型, 以免改变 fmt.Sprint 在非字符串参数之间添加空格的行为
+type nilToken struct{ text string }
+
+func (t nilToken) String() string { return t.text }
+
+// replaceNil 设置了 SetNilText 时, 返回将 nil 参数替换为该文本后的副本
+func (l *Logger) replaceNil(a []any) []any {
+	if l.nilText == "" {
+		return a
+	}
+	var replaced []any
+	for i, v := range a {
+		if isNil(v) {
+			if replaced == nil {
+				replaced = append([]any(nil), a...)
+			}
+			replaced[i] = nilToken{l.nilText}
+		}
+	}
+	if replaced == nil {
+		return a
+	}
+	return replaced
+}
@@ -0,0 +1,155 @@
+package SimpleLog
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrParseLine = errors.New("SimpleLog: not a SimpleLog text line")
+
+// timeLayouts 默认的自适应时间格式, 不含方括号
+var timeLayouts = []string{"15:04:05.999999999", "15:04:05", "15:04:05-|02", "15:04-|01/02"}
+
+// ParseLine 尽力将默认文本格式的一行解析回 Event, 可用于将旧的文本日志转为 JSON 等.
+// 局限:
+//   - 时间戳只含时分秒时, 日期取当天; 只含日时, 年月取当前, 且秒以下的精度取决于原格式
+//   - 末尾连续的 key=value 均视为字段, 值一律为字符串, 消息本身以 key=value 结尾时会被误认为字段
+//   - SetLevelTimeFormat 等自定义格式, 以及未开启 SetEscapeNewline 时含换行的消息无法解析
+//   - Caller 与调用栈不会出现在文本中, 解析结果中为空
+func ParseLine(s string) (*Event, error) {
+	s = stripANSI(strings.TrimSuffix(s, "\n"))
+	s = strings.TrimLeft(s, "▌█")
+
+	name, rest, ok := cutBracket(strings.TrimLeft(s, " "))
+	if !ok {
+		return nil, fmt.Errorf("%w: missing level: %q", ErrParseLine, s)
+	}
+	e := &Event{Level: -1}
+	for level, n := range levelNames {
+		if strings.TrimSpace(name) == n {
+			e.Level = Level(level)
+		}
+	}
+	if e.Level < 0 {
+		return nil, fmt.Errorf("%w: unknown level %q", ErrParseLine, name)
+	}
+
+	stamp, rest, ok := cutBracket(rest)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing timestamp: %q", ErrParseLine, s)
+	}
+	t, err := parseTimestamp(stamp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParseLine, err)
+	}
+	e.Time = t
+
+	if banner, after, ok := cutBracket(rest); ok {
+		e.Banner = "[" + banner + "]"
+		rest = after
+	}
+	if strings.HasPrefix(rest, " +") {
+		gap, after, _ := strings.Cut(rest[2:], " ")
+		if _, err := time.ParseDuration(gap); err == nil {
+			rest = " " + after
+		}
+	}
+	e.Message, e.Fields = splitFields(strings.TrimPrefix(rest, " "))
+	return e, nil
+}
+
+// cutBracket 切出 s 开头方括号中的内容
+func cutBracket(s string) (inner, rest string, ok bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", s, false
+	}
+	i := strings.IndexByte(s, ']')
+	if i < 0 {
+		return "", s, false
+	}
+	return s[1:i], s[i+1:], true
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	now := time.Now()
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			continue
+		}
+		year, month, day := now.Date()
+		if strings.Contains(layout, "01") {
+			month, day = t.Month(), t.Day()
+		} else if strings.Contains(layout, "02") {
+			day = t.Day()
+		}
+		return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown timestamp %q", s)
+}
+
+// splitFields 将 s 末尾连续的 key=value 解析为字段
+func splitFields(s string) (string, []Field) {
+	var fields []Field
+	for s != "" {
+		start, f, ok := lastField(s)
+		if !ok {
+			break
+		}
+		fields = append(fields, f)
+		s = s[:max(start-1, 0)] // 去掉字段前的空格
+	}
+	slices.Reverse(fields)
+	return s, fields
+}
+
+// lastField 解析 s 中最后一个 key=value, 返回其起始位置; 带引号的值可能含有空格
+func lastField(s string) (int, Field, bool) {
+	if !strings.HasSuffix(s, `"`) {
+		start := strings.LastIndexByte(s, ' ') + 1
+		key, value, ok := parseField(s[start:])
+		return start, String(key, value), ok
+	}
+	for i := strings.LastIndex(s, `="`); i > 0; i = strings.LastIndex(s[:i], `="`) {
+		start := strings.LastIndexByte(s[:i], ' ') + 1
+		if key, value, ok := parseField(s[start:]); ok {
+			return start, String(key, value), true
+		}
+	}
+	return 0, Field{}, false
+}
+
+func parseField(tok string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(tok, "=")
+	if !ok || key == "" || strings.ContainsAny(key, `" `) {
+		return "", "", false
+	}
+	if strings.HasPrefix(value, `"`) {
+		v, err := strconv.Unquote(value)
+		if err != nil {
+			return "", "", false
+		}
+		value = v
+	}
+	return key, value, true
+}
+
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += ansiLen(s[i:])
+			continue
+		}
+		sb.WriteByte(s[i])
+		i++
+	}
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// RingWriter 在内存中保留最近写入的 n 行, 可作为输出使用
+type RingWriter struct {
+	mu    sync.Mutex
+	lines [][]byte
+	next  int
+	full  bool
+}
+
+func NewRingWriter(n int) *RingWriter {
+	return &RingWriter{lines: make([][]byte, max(n, 1))}
+}
+
+func (r *RingWriter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for b := p; len(b) > 0; {
+		n := lineLen(b)
+		r.lines[r.next] = append(r.lines[r.next][:0], b[:n]...)
+		r.next++
+		if r.next == len(r.lines) {
+			r.next, r.full = 0, true
+		}
+		b = b[n:]
+	}
+	return len(p), nil
+}
+
+// WriteTo 按写入顺序输出保留的行
+func (r *RingWriter) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	var buf bytes.Buffer
+	if r.full {
+		for _, line := range r.lines[r.next:] {
+			buf.Write(line)
+		}
+	}
+	for _, line := range r.lines[:r.next] {
+		buf.Write(line)
+	}
+	r.mu.Unlock()
+	return buf.WriteTo(w)
+}
+
+// SetRingBuffer 添加一个保留最近 n 行的 RingWriter 作为输出, 供 DumpDiagnostics 使用
+func (l *Logger) SetRingBuffer(n int) *Logger {
+	r := NewRingWriter(n)
+	l.ring = r
+	return l.AddOutput(r)
+}
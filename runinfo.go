@@ -0,0 +1,78 @@
+package SimpleLog
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// LogRunContext 输出一行运行信息: cwd, args, pid, go_version,
+// 建议在启动时调用一次, 便于汇总日志后区分每次运行
+func (l *Logger) LogRunContext(level Level) {
+	if !l.levelOk(level) {
+		return
+	}
+	l.log(level, "run context", runContext())
+}
+
+func runContext() []Field {
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = err.Error()
+	}
+	return []Field{
+		String("cwd", cwd),
+		Any("args", os.Args[1:]),
+		Int("pid", os.Getpid()),
+		String("go_version", runtime.Version()),
+	}
+}
+
+// WithRuntimeStats 返回附带当前 goroutines, heap_alloc (字节) 与 num_gc 字段的副本, 语义同 Clone,
+// 适合在出错时记录系统状态. 字段的值在调用时取得;
+// runtime.ReadMemStats 会短暂地暂停整个程序 (stop-the-world), 应按需调用, 不要每行都用
+//
+//	logger.WithRuntimeStats().Error("out of workers")
+func (l *Logger) WithRuntimeStats() *Logger {
+	c := l.Clone()
+	c.fields = append(c.fields, runtimeStats()...)
+	return c
+}
+
+func runtimeStats() []Field {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return []Field{
+		Int("goroutines", runtime.NumGoroutine()),
+		Int("heap_alloc", int(ms.HeapAlloc)),
+		Int("num_gc", int(ms.NumGC)),
+	}
+}
+
+// DumpDiagnostics 向 w 写入一份可附在问题报告中的诊断信息:
+// 运行信息与版本, 主要配置, 以及 SetRingBuffer 保留的最近日志
+func (l *Logger) DumpDiagnostics(w io.Writer) error {
+	version := "(unknown)"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Path + "@" + info.Main.Version
+	}
+	cfg := l.peekSettings()
+	b := l.appendLine(cfg, nil, InfoLevel, "run context", append(runContext(), String("version", version)))
+	b = l.appendLine(cfg, b, InfoLevel, "config", []Field{
+		String("level", Level(l.live.level.Load()).String()),
+		Int("verbosity", l.verbosity),
+		Bool("json", l.settings().fullJSON),
+		Bool("color", l.settings().color),
+	})
+	if l.ring == nil {
+		b = append(b, "(no ring buffer, see SetRingBuffer)\n"...)
+	} else {
+		b = append(b, "--- recent logs ---\n"...)
+	}
+	if _, err := w.Write(b); err != nil || l.ring == nil {
+		return err
+	}
+	_, err := l.ring.WriteTo(w)
+	return err
+}
@@ -0,0 +1,48 @@
+package SimpleLog
+
+import "math"
+
+// SetKeySampleRate 设置 Sampled 的采样率, 取值 [0, 1], 默认为 1 即全部采样
+func (l *Logger) SetKeySampleRate(rate float64) *Logger {
+	l.keySampleRate = min(max(rate, 0), 1)
+	return l
+}
+
+// Sampled 按 key 的哈希决定是否采样, 同一 key 的结果始终一致,
+// 可用于以请求 ID 等为 key, 完整保留被采样请求的全部日志.
+// 被采样时返回 l 本身, 否则返回一个不输出任何日志的副本 (Output 除外)
+func (l *Logger) Sampled(key string) *Logger {
+	if keySampled(key, l.keySampleRate) {
+		return l
+	}
+	nop := *l
+	nop.view = newView(l.settings())
+	nop.nop = true
+	return &nop
+}
+
+func keySampled(key string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	return float64(mix64(fnv64a(key))) < rate*math.MaxUint64
+}
+
+func fnv64a(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// mix64 打散 fnv64a 的高位, 使相近的 key 也能均匀分布
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
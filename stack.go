@@ -0,0 +1,136 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// pkgPrefix 本包函数名的前缀, 用于从调用栈中剔除日志库自身的帧
+var pkgPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name() // github.com/Miuzarte/SimpleLog.init.func1
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	return name[:slash+1+dot+1]
+}()
+
+// caller 返回调用栈中第一个不属于日志库的帧, 测试文件视为调用方
+func caller() Caller {
+	var pcs [16]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, pkgPrefix) || strings.HasSuffix(f.File, "_test.go") {
+			return Caller{File: f.File, Line: f.Line, Func: f.Function}
+		}
+		if !more {
+			return Caller{}
+		}
+	}
+}
+
+// maxDepth stackDepth 统计的最大帧数
+const maxDepth = 1024
+
+// SetShowStackDepth 在每行末尾附加 depth=N 字段, 即调用处的调用栈深度 (不含日志库自身的帧),
+// 用于排查失控的递归. 每行都需要遍历调用栈, 默认关闭, 超过 1024 帧时记为 1024
+func (l *Logger) SetShowStackDepth(show bool) *Logger {
+	l.showDepth = show
+	return l
+}
+
+// stackDepth 返回调用栈中第一个不属于日志库的帧及其之后的帧数
+func stackDepth() int {
+	var pcs [maxDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	depth, inLib := 0, true
+	for more := n > 0; more; {
+		var f runtime.Frame
+		f, more = frames.Next()
+		if inLib && strings.HasPrefix(f.Function, pkgPrefix) && !strings.HasSuffix(f.File, "_test.go") {
+			continue
+		}
+		inLib = false
+		depth++
+	}
+	return min(depth, maxDepth)
+}
+
+// trimStack 剔除 debug.Stack 输出中日志库自身的帧,
+// 若处于 recover 中, 则一并剔除 panic 及之前 (defer 函数) 的帧
+func trimStack(stack []byte) []byte {
+	lines := bytes.Split(bytes.TrimSuffix(stack, []byte("\n")), []byte("\n"))
+	if len(lines) < 1 {
+		return stack
+	}
+	header, frames := lines[0], lines[1:]
+	for i := 0; i+1 < len(frames); i += 2 {
+		if bytes.HasPrefix(frames[i], []byte("panic(")) {
+			frames = frames[i+2:]
+			break
+		}
+	}
+	for len(frames) >= 2 &&
+		(bytes.HasPrefix(frames[0], []byte("runtime/debug.Stack(")) ||
+			bytes.HasPrefix(frames[0], []byte(pkgPrefix+"(*Logger)."))) {
+		frames = frames[2:]
+	}
+	b := make([]byte, 0, len(stack))
+	b = append(b, header...)
+	b = append(b, '\n')
+	for _, f := range frames {
+		b = append(b, f...)
+		b = append(b, '\n')
+	}
+	return b
+}
+
+// SetMaxStackDepth 限制 LogPanic, FakePanic 等输出的调用栈最多保留 n 帧,
+// 其余以 "...(N more frames)" 代替. 默认为 0, 即与 debug.Stack 一样不限制
+func (l *Logger) SetMaxStackDepth(n int) *Logger {
+	l.maxStackDepth = n
+	return l
+}
+
+// limitStack 保留 debug.Stack 格式的 stack 中的前 n 帧, n <= 0 时原样返回
+func limitStack(stack []byte, n int) []byte {
+	if n <= 0 {
+		return stack
+	}
+	lines := bytes.SplitAfter(stack, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	frames := (len(lines) - 1) / 2 // 首行为 goroutine 信息, 每帧两行
+	if frames <= n {
+		return stack
+	}
+	b := bytes.Join(lines[:1+2*n], nil)
+	return fmt.Appendf(b, "...(%d more frames)\n", frames-n)
+}
+
+// LogPanic 以 PanicLevel 输出 recover 得到的值及裁剪后的调用栈, 不会再次 panic,
+// 可用于自行决定是否重启的 goroutine 守护逻辑
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			logger.LogPanic(r)
+//		}
+//	}()
+func (l *Logger) LogPanic(recovered any) {
+	if !l.levelOk(PanicLevel) {
+		return
+	}
+	cfg := l.settings()
+	e := l.newEvent(cfg, time.Time{}, PanicLevel, fmt.Sprint("panic: ", recovered), nil)
+	if !l.filterEvent(&e) {
+		return
+	}
+	l.emit(cfg, &e, limitStack(trimStack(debug.Stack()), l.maxStackDepth))
+}
@@ -0,0 +1,83 @@
+package SimpleLog
+
+import "fmt"
+
+// SugaredLogger 便捷接口, 支持 printf 风格与松散的键值对,
+// 与 Logger 共享同一份配置, 可通过 Desugar 随时切换回带类型的 Logger
+type SugaredLogger struct {
+	base *Logger
+}
+
+func (l *Logger) Sugar() *SugaredLogger {
+	return &SugaredLogger{base: l}
+}
+
+func (s *SugaredLogger) Desugar() *Logger {
+	return s.base
+}
+
+// sweeten 将交替出现的键与值转为 Field, 也可以直接传入 Field,
+// 非字符串的键以 fmt.Sprint 转换, 落单的值以 "!BADKEY" 为键
+func sweeten(keysAndValues []any) []Field {
+	fields := make([]Field, 0, len(keysAndValues)/2+1)
+	for i := 0; i < len(keysAndValues); {
+		switch k := keysAndValues[i].(type) {
+		case Field:
+			fields = append(fields, k)
+			i++
+			continue
+		case string:
+			if i+1 < len(keysAndValues) {
+				fields = append(fields, Any(k, keysAndValues[i+1]))
+				i += 2
+				continue
+			}
+		default:
+			if i+1 < len(keysAndValues) {
+				fields = append(fields, Any(fmt.Sprint(k), keysAndValues[i+1]))
+				i += 2
+				continue
+			}
+		}
+		fields = append(fields, Any("!BADKEY", keysAndValues[i]))
+		i++
+	}
+	return fields
+}
+
+func (s *SugaredLogger) logw(level Level, msg string, keysAndValues []any) {
+	if !s.base.levelOk(level) {
+		return
+	}
+	s.base.log(level, msg, sweeten(keysAndValues))
+}
+
+func (s *SugaredLogger) Trace(a ...any)                 { s.base.Trace(a...) }
+func (s *SugaredLogger) Tracef(format string, a ...any) { s.base.Tracef(format, a...) }
+func (s *SugaredLogger) Tracew(msg string, keysAndValues ...any) {
+	s.logw(TraceLevel, msg, keysAndValues)
+}
+
+func (s *SugaredLogger) Debug(a ...any)                 { s.base.Debug(a...) }
+func (s *SugaredLogger) Debugf(format string, a ...any) { s.base.Debugf(format, a...) }
+func (s *SugaredLogger) Debugw(msg string, keysAndValues ...any) {
+	s.logw(DebugLevel, msg, keysAndValues)
+}
+
+func (s *SugaredLogger) Info(a ...any)                 { s.base.Info(a...) }
+func (s *SugaredLogger) Infof(format string, a ...any) { s.base.Infof(format, a...) }
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...any) {
+	s.logw(InfoLevel, msg, keysAndValues)
+}
+
+func (s *SugaredLogger) Warn(a ...any)                 { s.base.Warn(a...) }
+func (s *SugaredLogger) Warnf(format string, a ...any) { s.base.Warnf(format, a...) }
+func (s *SugaredLogger) Warnw(msg string, keysAndValues ...any) {
+	s.logw(WarnLevel, msg, keysAndValues)
+}
+
+func (s *SugaredLogger) Error(a ...any)                 { s.base.Error(a...) }
+func (s *SugaredLogger) Errorf(format string, a ...any) { s.base.Errorf(format, a...) }
+func (s *SugaredLogger) Errorw(msg string, keysAndValues ...any) {
+	s.logw(ErrorLevel, msg, keysAndValues)
+}
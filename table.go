@@ -0,0 +1,86 @@
+package SimpleLog
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Table 以对齐的列输出表格, 与 Block 一样在一次加锁内写入,
+// 列宽按终端显示宽度计算, 忽略 ANSI 转义序列, 宽字符占两列.
+// JSON 输出中每行数据为一条日志, 以表头为键
+func (l *Logger) Table(level Level, headers []string, rows [][]string) {
+	if !l.levelOk(level) {
+		return
+	}
+	var b, jb []byte
+	cfg, jsonOn := l.settings(), l.live.json.Load()
+	if !cfg.fullJSON {
+		for _, s := range alignColumns(headers, rows) {
+			if e, ok := l.blockEvent(cfg, level, s, nil); ok {
+				l.recordLine(level, e.Message, e.Time.UnixNano())
+				b = l.appendEvent(cfg, b, &e, nil)
+			}
+		}
+	}
+	if cfg.fullJSON || jsonOn {
+		for _, row := range rows {
+			e, ok := l.blockEvent(cfg, level, "", rowFields(headers, row))
+			if !ok {
+				continue
+			}
+			if cfg.fullJSON {
+				l.recordLine(level, e.Message, e.Time.UnixNano())
+				b = l.appendJSON(b, &e, nil)
+			}
+			if jsonOn {
+				jb = l.appendJSON(jb, &e, nil)
+			}
+		}
+	}
+	if len(b) > 0 || len(jb) > 0 {
+		l.write(level, b, jb)
+	}
+}
+
+// alignColumns 返回表头与各行对齐后的文本, 列间隔两个空格, 最后一列不补齐
+func alignColumns(headers []string, rows [][]string) []string {
+	all := append([][]string{headers}, rows...)
+	var widths []int
+	for _, row := range all {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], DisplayWidth(cell))
+		}
+	}
+	lines := make([]string, 0, len(all))
+	var sb strings.Builder
+	for _, row := range all {
+		sb.Reset()
+		for i, cell := range row {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			sb.WriteString(cell)
+			if i < len(row)-1 {
+				sb.WriteString(strings.Repeat(" ", widths[i]-DisplayWidth(cell)))
+			}
+		}
+		lines = append(lines, sb.String())
+	}
+	return lines
+}
+
+// rowFields 以表头为键, 超出表头的列以其序号为键
+func rowFields(headers, row []string) []Field {
+	fields := make([]Field, len(row))
+	for i, cell := range row {
+		key := strconv.Itoa(i)
+		if i < len(headers) {
+			key = headers[i]
+		}
+		fields[i] = String(key, cell)
+	}
+	return fields
+}
@@ -0,0 +1,50 @@
+package SimpleLog
+
+// VerboseLogger 由 V 返回, 用法同 klog 的 V 级别
+type VerboseLogger struct {
+	l       *Logger
+	level   Level
+	enabled bool
+}
+
+// SetVerbosity 设置 V 的门槛, V(v) 仅在 v <= verbosity 时输出, 默认为 0
+func (l *Logger) SetVerbosity(verbosity int) *Logger {
+	l.verbosity = verbosity
+	return l
+}
+
+// V 返回按详细程度控制的 VerboseLogger, 是否输出只取决于 SetVerbosity, 不受 SetLevel 影响.
+// V(0) 以 InfoLevel 输出, V(1) 以 DebugLevel, V(2) 及以上以 TraceLevel
+func (l *Logger) V(verbosity int) VerboseLogger {
+	level := InfoLevel
+	switch {
+	case verbosity >= 2:
+		level = TraceLevel
+	case verbosity == 1:
+		level = DebugLevel
+	}
+	return VerboseLogger{l: l, level: level, enabled: !l.nop && verbosity <= l.verbosity}
+}
+
+// Enabled 是否输出, 可用于跳过开销较大的参数计算
+func (v VerboseLogger) Enabled() bool {
+	return v.enabled
+}
+
+func (v VerboseLogger) Info(a ...any) {
+	if v.enabled {
+		v.l.Print(v.level, a...)
+	}
+}
+
+func (v VerboseLogger) Infof(format string, a ...any) {
+	if v.enabled {
+		v.l.Printf(v.level, format, a...)
+	}
+}
+
+func (v VerboseLogger) InfoKV(kvs ...Field) {
+	if v.enabled {
+		v.l.log(v.level, "", kvs)
+	}
+}
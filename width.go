@@ -0,0 +1,67 @@
+package SimpleLog
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// DisplayWidth 返回 s 在终端中占用的列数, 忽略 ANSI 转义序列,
+// 东亚宽字符与 emoji 计为 2 列, 组合字符与格式字符计为 0 列, 可用于自行对齐彩色输出
+func DisplayWidth(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += ansiLen(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		w += runeWidth(r)
+	}
+	return w
+}
+
+// ansiLen 返回 s 开头的转义序列的长度, s[0] 为 ESC
+func ansiLen(s string) int {
+	if len(s) < 2 || s[1] != '[' {
+		return min(2, len(s))
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// isWide East Asian Width 为 W 或 F 的常见区段
+func isWide(r rune) bool {
+	return r >= 0x1100 && r <= 0x115f || // 谚文字母
+		r >= 0x2e80 && r <= 0x303e || // CJK 部首, 符号与标点
+		r >= 0x3041 && r <= 0x33ff || // 假名, 注音, CJK 兼容
+		r >= 0x3400 && r <= 0x4dbf || // CJK 扩展 A
+		r >= 0x4e00 && r <= 0x9fff || // CJK 统一汉字
+		r >= 0xa000 && r <= 0xa4cf || // 彝文
+		r >= 0xac00 && r <= 0xd7a3 || // 谚文音节
+		r >= 0xf900 && r <= 0xfaff || // CJK 兼容汉字
+		r >= 0xfe30 && r <= 0xfe4f || // CJK 兼容形式
+		r >= 0xff00 && r <= 0xff60 || // 全角字符
+		r >= 0xffe0 && r <= 0xffe6 ||
+		r >= 0x1f300 && r <= 0x1f64f || // emoji
+		r >= 0x1f900 && r <= 0x1f9ff ||
+		r >= 0x20000 && r <= 0x3fffd // CJK 扩展 B 及以后
+}
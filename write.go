@@ -0,0 +1,319 @@
+package SimpleLog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrWriteTimeout = errors.New("SimpleLog: write timeout")
+
+type counters struct {
+	dropped atomic.Uint64
+	first   atomic.Pointer[firstError]
+	last    atomic.Int64  // 最近一行日志的输出时间 (UnixNano), 取自时钟而非事件时间
+	lines   atomic.Uint64 // 已输出的行数
+}
+
+type firstError struct {
+	level Level
+	msg   string
+	nanos int64
+}
+
+// FirstError 返回第一条 ErrorLevel 及以上的日志, 可在退出时作为失败原因,
+// ok 为 false 表示尚未出现. 被 filter 丢弃的日志不计入
+func (l *Logger) FirstError() (level Level, msg string, t time.Time, ok bool) {
+	fe := l.counters.first.Load()
+	if fe == nil {
+		return 0, "", time.Time{}, false
+	}
+	return fe.level, fe.msg, time.Unix(0, fe.nanos), true
+}
+
+// recordLine 记录输出的一行, 由 emit, Block 与 Table 调用, nanos 为事件时间.
+// 调用方传入时间戳而非 time.Time, msg 仅在需要时复制, 以免事件逃逸到堆上
+func (l *logger) recordLine(level Level, msg string, nanos int64) {
+	c := l.counters
+	if level >= ErrorLevel && c.first.Load() == nil {
+		c.first.CompareAndSwap(nil, &firstError{level, strings.Clone(msg), nanos})
+	}
+	c.last.Store(l.timeNow().UnixNano()) // LogAt 回放的旧事件同样算作最近的活动
+	c.lines.Add(1)
+}
+
+// Stats 日志统计
+type Stats struct {
+	Dropped uint64 // 因写入超时, Close 之后丢弃等原因被丢弃的行数
+}
+
+func (l *Logger) Stats() Stats {
+	return Stats{Dropped: l.counters.dropped.Load()}
+}
+
+// SetOnError 设置写入出错时的回调, 回调在锁外执行, 可以在其中输出日志,
+// 未设置时错误写入 MetaOutput
+func (l *Logger) SetOnError(fn func(err error)) *Logger {
+	l.onError = fn
+	return l
+}
+
+// SetWriteTimeout 限制每次写入的耗时, 超时的行被丢弃并计入 Stats.Dropped,
+// 同时以 ErrWriteTimeout 调用 OnError, 传入 0 关闭.
+//
+// 写入在单独的 goroutine 中进行, 超时后该 goroutine 无法被取消;
+// 为避免永久卡住的输出不断泄漏 goroutine, 在上一次超时的写入返回之前,
+// 后续的行会被直接丢弃, 因此最多只会遗留一个 goroutine,
+// 该状态与 Clone, WithField 等派生的实例共享, 期间经由它们输出的行同样被丢弃.
+func (l *Logger) SetWriteTimeout(d time.Duration) *Logger {
+	l.writeTimeout = d
+	return l
+}
+
+// levelOff 高于所有级别, 用于关闭按级别触发的功能
+const levelOff = PanicLevel + 1
+
+// SetSyncLevel 对不低于 min 的级别, 写入后立即 Flush 所有缓冲,
+// 并对 *os.File 等实现了 Sync() error 的输出调用 Sync, 保证崩溃前该行已落盘
+func (l *Logger) SetSyncLevel(min Level) *Logger {
+	l.syncLevel = min
+	return l
+}
+
+// LevelWriter 可感知级别的输出, 作为 Out 或 AddOutput 的输出时,
+// 以 WriteLevel 代替 Write, 其中 p 为格式化后的完整一行 (或多行, 如 Block).
+// Output 写入的内容不带级别, 视为 TraceLevel
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level Level, p []byte) (int, error)
+}
+
+func writeLevel(w io.Writer, level Level, p []byte) (int, error) {
+	if lw, ok := w.(LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Write(p)
+}
+
+// multiWriter 同 io.MultiWriter, 但保留各个输出以便逐个 Sync 或按级别写入
+type multiWriter []io.Writer
+
+func (mw multiWriter) Write(p []byte) (int, error) {
+	return mw.WriteLevel(TraceLevel, p)
+}
+
+func (mw multiWriter) WriteLevel(level Level, p []byte) (int, error) {
+	for _, w := range mw {
+		n, err := writeLevel(w, level, p)
+		if err != nil {
+			return n, err
+		}
+		if n != len(p) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return len(p), nil
+}
+
+type syncer interface {
+	Sync() error
+}
+
+// syncOutputs 依次落盘 w 及其包含的所有输出
+func syncOutputs(w io.Writer) error {
+	switch w := w.(type) {
+	case multiWriter:
+		var errs []error
+		for _, w := range w {
+			errs = append(errs, syncOutputs(w))
+		}
+		return errors.Join(errs...)
+	case *bufferedWriter:
+		return errors.Join(w.Flush(), syncOutputs(w.w))
+	case syncer:
+		// 终端与管道不支持 Sync, 忽略
+		if err := w.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, errors.ErrUnsupported) {
+			return err
+		}
+	}
+	return nil
+}
+
+const truncatedMarker = "...(truncated)"
+
+// SetMaxLineBytes 限制格式化后每行的字节数 (含换行符), 超出部分替换为 "...(truncated)",
+// 截断点不会落在多字节字符中间, 换行符保留. 对所有输出生效, 截断后的 JSON 行不再完整. 传入 0 关闭
+func (l *Logger) SetMaxLineBytes(n int) *Logger {
+	l.maxLineBytes = n
+	return l
+}
+
+// truncateLines 截断 b 中超过 limit 字节的行, 没有需要截断的行时原样返回
+func truncateLines(b []byte, limit int) []byte {
+	if limit <= 0 || !hasLongLine(b, limit) {
+		return b
+	}
+	out := make([]byte, 0, len(b))
+	for len(b) > 0 {
+		n := lineLen(b)
+		out = appendTruncated(out, b[:n], limit)
+		b = b[n:]
+	}
+	return out
+}
+
+// lineLen 返回 b 中第一行的长度, 含换行符
+func lineLen(b []byte) int {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return i + 1
+	}
+	return len(b)
+}
+
+func hasLongLine(b []byte, limit int) bool {
+	for len(b) > 0 {
+		n := lineLen(b)
+		if n > limit {
+			return true
+		}
+		b = b[n:]
+	}
+	return false
+}
+
+func appendTruncated(dst, line []byte, limit int) []byte {
+	if len(line) <= limit {
+		return append(dst, line...)
+	}
+	nl := line[len(line)-1] == '\n'
+	room := limit
+	if nl {
+		line = line[:len(line)-1]
+		room--
+	}
+	marker := truncatedMarker[:min(len(truncatedMarker), room)]
+	keep := room - len(marker)
+	for keep > 0 && !utf8.RuneStart(line[keep]) {
+		keep--
+	}
+	dst = append(dst, line[:keep]...)
+	dst = append(dst, marker...)
+	if nl {
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+// write 将文本行 b 写入 Out, JSON 行 jb 写入 AddJSONOutput 添加的输出
+func (l *Logger) write(level Level, b, jb []byte) {
+	l.Lock()
+	b, jb = truncateLines(b, l.maxLineBytes), truncateLines(jb, l.maxLineBytes)
+	if l.closed && l.postClose == PostCloseDrop {
+		l.Unlock()
+		l.counters.dropped.Add(1)
+		l.handleError(ErrClosed)
+		return
+	}
+	var err error
+	if l.breaker != nil {
+		err = l.breaker.writeLocked(l, l.Out, level, b)
+	} else {
+		err = l.writeLocked(l.Out, level, b)
+	}
+	if jb != nil && l.jsonOut != nil {
+		err = errors.Join(err, l.writeLocked(l.jsonOut, level, jb))
+	}
+	if errors.Is(err, ErrWriteTimeout) { // 文本与 JSON 都超时也只算丢弃一行
+		l.counters.dropped.Add(1)
+	}
+	if err == nil && level >= l.syncLevel {
+		err = errors.Join(syncOutputs(l.Out), syncOutputs(l.jsonOut))
+	}
+	l.Unlock()
+	if err != nil {
+		l.handleError(err)
+	}
+}
+
+// pendingWrite 超时后仍未返回的写入, 由锁保护, 与 Clone 出的实例共享,
+// 以保证无论派生多少实例, 卡住的输出最多只遗留一个 goroutine
+type pendingWrite struct {
+	done chan struct{}
+}
+
+func (l *logger) writeLocked(w io.Writer, level Level, b []byte) error {
+	if l.writeTimeout <= 0 {
+		_, err := writeLevel(w, level, b)
+		return err
+	}
+	return l.writeWithTimeout(w, level, b)
+}
+
+// writing 报告是否有超时后仍未返回的写入, 此时该写入可能仍在访问输出 (包括缓冲输出), 须持锁调用
+func (l *logger) writing() bool {
+	if l.pending.done == nil {
+		return false
+	}
+	select {
+	case <-l.pending.done:
+		l.pending.done = nil
+		return false
+	default:
+		return true
+	}
+}
+
+// writeWithTimeout 超时或仍有未返回的写入时返回 ErrWriteTimeout, 丢弃的计数由 write 负责
+func (l *logger) writeWithTimeout(w io.Writer, level Level, b []byte) error {
+	if l.writing() {
+		return ErrWriteTimeout
+	}
+
+	b = append([]byte(nil), b...) // b 来自缓冲池, 超时返回后可能被复用
+	done := make(chan struct{})
+	var err error
+	go func(out io.Writer) {
+		_, err = writeLevel(out, level, b)
+		close(done)
+	}(w)
+
+	timer := time.NewTimer(l.writeTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return err
+	case <-timer.C:
+		l.pending.done = done
+		return ErrWriteTimeout
+	}
+}
+
+// handleError 设置了 OnError 时交由其处理, 否则作为诊断信息写入 MetaOutput
+func (l *Logger) handleError(err error) {
+	if l.onError != nil {
+		l.onError(err)
+		return
+	}
+	l.writeMeta(ErrorLevel, "SimpleLog: "+strings.TrimPrefix(err.Error(), "SimpleLog: "))
+}
+
+// SetMetaOutput 设置日志系统自身诊断信息的输出, 包括未设置 OnError 时的写入错误,
+// 以及熔断的切换与恢复等, 与应用日志分开, 默认为 os.Stderr
+func (l *Logger) SetMetaOutput(w io.Writer) *Logger {
+	l.metaOut = w
+	return l
+}
+
+func (l *Logger) writeMeta(level Level, msg string) {
+	w := l.metaOut
+	if w == nil {
+		w = os.Stderr
+	}
+	w.Write(l.appendLine(l.peekSettings(), nil, level, msg, nil)) // 不消耗主输出的日期与间隔标记
+}